@@ -0,0 +1,8 @@
+package api
+
+import _ "embed"
+
+// OpenAPI describes arbiter HTTP API.
+//
+//go:embed openapi.yaml
+var OpenAPI []byte
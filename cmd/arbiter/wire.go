@@ -9,6 +9,7 @@ import (
 	"github.com/JeremyLoy/config"
 	"github.com/damejeras/shootout/internal/app"
 	"github.com/damejeras/shootout/internal/control"
+	"github.com/damejeras/shootout/internal/infrastructure"
 	"github.com/damejeras/shootout/internal/shootout"
 	"github.com/go-redis/redis/v8"
 	"github.com/google/wire"
@@ -20,6 +21,7 @@ func InitArbiter(cfg *app.ArbiterConfig) (*control.Arbiter, error) {
 		initRedisClient,
 		control.NewArbiter,
 		shootout.NewState,
+		infrastructure.NewHub,
 	)
 
 	return nil, nil
@@ -31,6 +33,10 @@ func initConfig() (*app.ArbiterConfig, error) {
 		return nil, err
 	}
 
+	if err := shootout.DecodeConfig(&cfg); err != nil {
+		return nil, err
+	}
+
 	return &cfg, nil
 }
 
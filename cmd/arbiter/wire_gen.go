@@ -10,6 +10,7 @@ import (
 	"github.com/JeremyLoy/config"
 	"github.com/damejeras/shootout/internal/app"
 	"github.com/damejeras/shootout/internal/control"
+	"github.com/damejeras/shootout/internal/infrastructure"
 	"github.com/damejeras/shootout/internal/shootout"
 	"github.com/go-redis/redis/v8"
 	"log"
@@ -24,7 +25,8 @@ func InitArbiter(cfg *app.ArbiterConfig) (*control.Arbiter, error) {
 	if err != nil {
 		return nil, err
 	}
-	arbiter := control.NewArbiter(cfg, state, logger, client)
+	hub := infrastructure.NewHub()
+	arbiter := control.NewArbiter(cfg, state, logger, client, hub)
 	return arbiter, nil
 }
 
@@ -42,6 +44,10 @@ func initConfig() (*app.ArbiterConfig, error) {
 		return nil, err
 	}
 
+	if err := shootout.DecodeConfig(&cfg); err != nil {
+		return nil, err
+	}
+
 	return &cfg, nil
 }
 
@@ -0,0 +1,12 @@
+package main
+
+import "log"
+
+func main() {
+	cfg, err := initConfig()
+	if err != nil {
+		log.Fatalf("init config: %v", err)
+	}
+
+	InitServer(cfg).Run()
+}
@@ -0,0 +1,37 @@
+//go:build wireinject
+// +build wireinject
+
+package main
+
+import (
+	"log"
+
+	"github.com/JeremyLoy/config"
+	"github.com/damejeras/shootout/internal/app"
+	"github.com/damejeras/shootout/internal/gym"
+	"github.com/damejeras/shootout/internal/shootout"
+	"github.com/google/wire"
+)
+
+func InitServer(cfg *app.ArbiterConfig) *gym.Server {
+	wire.Build(
+		log.Default,
+		gym.NewServer,
+	)
+
+	return nil
+}
+
+// initConfig reads game configuration in the same way arbiter does.
+func initConfig() (*app.ArbiterConfig, error) {
+	var cfg app.ArbiterConfig
+	if err := config.FromEnv().To(&cfg); err != nil {
+		return nil, err
+	}
+
+	if err := shootout.DecodeConfig(&cfg); err != nil {
+		return nil, err
+	}
+
+	return &cfg, nil
+}
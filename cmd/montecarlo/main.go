@@ -0,0 +1,33 @@
+package main
+
+import (
+	"io"
+	"log"
+	"os"
+)
+
+func main() {
+	cfg, err := initConfig()
+	if err != nil {
+		log.Fatalf("init config: %v", err)
+	}
+
+	monteCarlo, err := InitMonteCarlo(cfg)
+	if err != nil {
+		log.Fatalf("init monte carlo: %v", err)
+	}
+
+	// every shot of thousands of games is not worth reading
+	log.SetOutput(io.Discard)
+
+	report, err := monteCarlo.Run()
+	if err != nil {
+		log.SetOutput(os.Stderr)
+		log.Fatalf("run games: %v", err)
+	}
+
+	if err := monteCarlo.Write(os.Stdout, report); err != nil {
+		log.SetOutput(os.Stderr)
+		log.Fatalf("write report: %v", err)
+	}
+}
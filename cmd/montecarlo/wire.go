@@ -0,0 +1,41 @@
+//go:build wireinject
+// +build wireinject
+
+package main
+
+import (
+	"github.com/JeremyLoy/config"
+	"github.com/damejeras/shootout/internal/app"
+	"github.com/damejeras/shootout/internal/shootout"
+	"github.com/damejeras/shootout/internal/simulation"
+	"github.com/google/wire"
+)
+
+func InitMonteCarlo(cfg *app.MonteCarloConfig) (*simulation.MonteCarlo, error) {
+	wire.Build(
+		simulation.NewMonteCarlo,
+	)
+
+	return nil, nil
+}
+
+// initConfig reads game configuration in the same way arbiter does.
+func initConfig() (*app.MonteCarloConfig, error) {
+	var cfg app.MonteCarloConfig
+	if err := config.FromEnv().To(&cfg); err != nil {
+		return nil, err
+	}
+
+	var game app.ArbiterConfig
+	if err := config.FromEnv().To(&game); err != nil {
+		return nil, err
+	}
+
+	if err := shootout.DecodeConfig(&game); err != nil {
+		return nil, err
+	}
+
+	cfg.Game = &game
+
+	return &cfg, nil
+}
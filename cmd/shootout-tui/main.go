@@ -0,0 +1,17 @@
+package main
+
+import "log"
+
+func main() {
+	cfg, err := initConfig()
+	if err != nil {
+		log.Fatalf("init config: %v", err)
+	}
+
+	viewer, err := InitViewer(cfg)
+	if err != nil {
+		log.Fatalf("init viewer: %v", err)
+	}
+
+	viewer.Run()
+}
@@ -0,0 +1,50 @@
+//go:build wireinject
+// +build wireinject
+
+package main
+
+import (
+	"log"
+
+	"github.com/JeremyLoy/config"
+	"github.com/damejeras/shootout/internal/app"
+	"github.com/damejeras/shootout/internal/control"
+	"github.com/damejeras/shootout/internal/tui"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/wire"
+)
+
+func InitViewer(cfg *app.ViewerConfig) (*control.Viewer, error) {
+	wire.Build(
+		log.Default,
+		initRedisClient,
+		tui.NewBoard,
+		control.NewViewer,
+	)
+
+	return nil, nil
+}
+
+func initConfig() (*app.ViewerConfig, error) {
+	var cfg app.ViewerConfig
+	if err := config.FromEnv().To(&cfg); err != nil {
+		return nil, err
+	}
+
+	return &cfg, nil
+}
+
+func initRedisClient(cfg *app.ViewerConfig) (*redis.Client, error) {
+	wire.Build(
+		redis.NewClient,
+		initRedisConfig,
+	)
+
+	return nil, nil
+}
+
+func initRedisConfig(cfg *app.ViewerConfig) *redis.Options {
+	return &redis.Options{
+		Addr: cfg.RedisAddr,
+	}
+}
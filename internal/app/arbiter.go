@@ -1,7 +1,110 @@
 package app
 
+import "time"
+
 type ArbiterConfig struct {
-	Port        string `config:"PORT"`
-	RedisAddr   string `config:"REDIS_ADDR"`
-	Competitors int    `config:"COMPETITORS"`
+	Port                string        `config:"PORT"`
+	RedisAddr           string        `config:"REDIS_ADDR"`
+	Competitors         int           `config:"COMPETITORS"`
+	MinCompetitors      int           `config:"MIN_COMPETITORS"`
+	RegistrationTimeout time.Duration `config:"REGISTRATION_TIMEOUT"`
+	TickInterval        time.Duration `config:"TICK_INTERVAL"`
+	// TurnBased arbiter starts the next round as soon as every competitor
+	// acted, TurnTimeout limits how long it waits for them.
+	TurnBased           bool          `config:"TURN_BASED"`
+	TurnTimeout         time.Duration `config:"TURN_TIMEOUT"`
+	MinHealth           int           `config:"MIN_HEALTH"`
+	MaxHealth           int           `config:"MAX_HEALTH"`
+	MaxDamage           int           `config:"MAX_DAMAGE"`
+	StatBudget          int           `config:"STAT_BUDGET"` // stats are free when budget is not set
+	ExpectedNames       []string      `config:"EXPECTED_NAMES"`
+	ProbabilisticCombat bool          `config:"PROBABILISTIC_COMBAT"`
+	Seed                int64         `config:"SEED"`
+	CritChance          int           `config:"CRIT_CHANCE"`
+	CritDamage          int           `config:"CRIT_DAMAGE"`
+	FriendlyFire        bool          `config:"FRIENDLY_FIRE"`
+	// FriendlyFirePenalty is a percentage of damage inflicted to a teammate
+	// which shooter takes as well.
+	FriendlyFirePenalty int `config:"FRIENDLY_FIRE_PENALTY"`
+	HealAmount          int `config:"HEAL_AMOUNT"`
+	// AimBonus is a percentage of damage added to aimed shot.
+	AimBonus int `config:"AIM_BONUS"`
+	// WeaponCatalog is JSON object of weapons by name, DecodeConfig decodes it
+	// into Armory. Decoded fields have no environment keys of their own, so
+	// they are named apart from raw ones, keys are matched ignoring case.
+	WeaponCatalog string `config:"WEAPONS"`
+	Armory        map[string]*Weapon
+	// FogOfWar sends every competitor its own view of the round, where
+	// enemies' health and damage are concealed. State and competitors are
+	// served over HTTP once the game is finished, event stream is not concealed.
+	FogOfWar bool `config:"FOG_OF_WAR"`
+	// Rules is a name of the ruleset game is played by.
+	Rules string `config:"RULES"`
+	// RulesScript is Starlark source of hooks adjusting the ruleset.
+	RulesScript string `config:"RULES_SCRIPT"`
+	// RulesScriptSteps limits execution steps of a single hook call.
+	RulesScriptSteps int `config:"RULES_SCRIPT_STEPS"`
+	// RulesScriptTimeout limits duration of a single hook call.
+	RulesScriptTimeout time.Duration `config:"RULES_SCRIPT_TIMEOUT"`
+	// MaxRounds after which sudden death rule kicks in, zero means unlimited.
+	MaxRounds int `config:"MAX_ROUNDS"`
+	// SuddenDeath is one of escalation, shrink and health, defaults to health.
+	// Escalation multiplies damage of shots and shrinks health like shrink.
+	SuddenDeath string `config:"SUDDEN_DEATH"`
+	// ItemCatalog is JSON object of effects items apply to their users by
+	// item name, DecodeConfig decodes it into ItemEffects.
+	ItemCatalog string `config:"ITEMS"`
+	ItemEffects map[string]*Effect
+	// ArenaLayout is JSON object of the arena, DecodeConfig decodes it into
+	// Battlefield.
+	ArenaLayout string `config:"ARENA"`
+	Battlefield *Arena
+	// BaseHitChanceSetting is decoded by DecodeConfig into BaseHitChance,
+	// which is nil when chance is not set, so zero chance is not taken for the
+	// default.
+	BaseHitChanceSetting string `config:"BASE_HIT_CHANCE"`
+	BaseHitChance        *int
+}
+
+// Weapon competitors can choose at registration. Weapon's damage is added to
+// competitor's damage.
+type Weapon struct {
+	Magazine  int `json:"magazine"`
+	Reload    int `json:"reload"`
+	MinDamage int `json:"minDamage"`
+	MaxDamage int `json:"maxDamage"`
+	// Ammo carried besides loaded magazine.
+	Ammo int `json:"ammo"`
+	// Effect is applied to competitors hit with the weapon.
+	Effect *Effect `json:"effect"`
+}
+
+// Effect persisting across rounds, kind is one of bleeding, stunned and shield.
+type Effect struct {
+	Kind   string `json:"kind"`
+	Rounds int    `json:"rounds"`
+	// Amount is damage per round of bleeding or damage absorbed by shield.
+	Amount int `json:"amount"`
+}
+
+// Arena is a grid competitors fight in. Without arena everyone can hit everyone.
+type Arena struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+	// Range limits shooting distance in cells, zero means unlimited range.
+	Range int `json:"range,omitempty"`
+	// Falloff is a percentage of damage lost for every cell of distance beyond the first.
+	Falloff int `json:"falloff,omitempty"`
+	// Move is a number of cells competitor can move in a round, defaults to 1.
+	Move int `json:"move"`
+	// Obstacles block movement and line of sight.
+	Obstacles []Position `json:"obstacles,omitempty"`
+	// Spawns are taken in order of registration, random free cells are used
+	// when there are no spawns left.
+	Spawns []Position `json:"spawns,omitempty"`
+}
+
+type Position struct {
+	X int `json:"x"`
+	Y int `json:"y"`
 }
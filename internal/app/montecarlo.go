@@ -0,0 +1,16 @@
+package app
+
+type MonteCarloConfig struct {
+	// Roster is JSON array of shooters, each of them can name a strategy.
+	Roster string `config:"ROSTER"`
+	Games  int    `config:"GAMES"`
+	// Workers play games in parallel, defaults to number of CPUs.
+	Workers int `config:"WORKERS"`
+	// RoundLimit stops games that go nowhere, they are counted as undecided.
+	RoundLimit int `config:"ROUND_LIMIT"`
+	// Format of the report is one of table, csv and json.
+	Format string `config:"FORMAT"`
+	// Game is configured in the same way as arbiter, it is not read from
+	// environment by itself but filled with the arbiter's configuration.
+	Game *ArbiterConfig
+}
@@ -1,9 +1,30 @@
 package app
 
+import "time"
+
 type ShooterConfig struct {
-	RedisAddr   string `config:"REDIS_ADDR"`
-	ArbiterAddr string `config:"ARBITER_ADDR"`
-	Name        string `config:"SHOOTER_NAME"`
-	Health      int    `config:"SHOOTER_HEALTH"`
-	Damage      int    `config:"SHOOTER_DAMAGE"`
+	RedisAddr   string   `config:"REDIS_ADDR"`
+	ArbiterAddr string   `config:"ARBITER_ADDR"`
+	Key         string   `config:"SHOOTER_KEY"`
+	Name        string   `config:"SHOOTER_NAME"`
+	Health      int      `config:"SHOOTER_HEALTH"`
+	Damage      int      `config:"SHOOTER_DAMAGE"`
+	Accuracy    int      `config:"SHOOTER_ACCURACY"`
+	Armor       int      `config:"SHOOTER_ARMOR"`
+	Speed       int      `config:"SHOOTER_SPEED"`
+	Team        string   `config:"SHOOTER_TEAM"`
+	Weapon      string   `config:"SHOOTER_WEAPON"`
+	Items       []string `config:"SHOOTER_ITEMS"`
+	// HeartbeatTimeout is the longest shooter waits for arbiter's event.
+	HeartbeatTimeout time.Duration `config:"HEARTBEAT_TIMEOUT"`
+	// Webhook is URL of the bot which decides shooter's actions, built-in
+	// strategy plays when it is not set.
+	Webhook        string        `config:"SHOOTER_WEBHOOK"`
+	WebhookTimeout time.Duration `config:"SHOOTER_WEBHOOK_TIMEOUT"`
+	// Plugin is path to WebAssembly module which decides shooter's actions,
+	// it takes precedence over webhook.
+	Plugin        string        `config:"SHOOTER_PLUGIN"`
+	PluginTimeout time.Duration `config:"SHOOTER_PLUGIN_TIMEOUT"`
+	// PluginMemoryPages limits plugin's memory in 64KiB pages.
+	PluginMemoryPages int `config:"SHOOTER_PLUGIN_MEMORY_PAGES"`
 }
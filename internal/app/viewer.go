@@ -0,0 +1,11 @@
+package app
+
+import "time"
+
+type ViewerConfig struct {
+	ArbiterAddr    string        `config:"ARBITER_ADDR"`
+	RedisAddr      string        `config:"REDIS_ADDR"`
+	RecordFile     string        `config:"RECORD_FILE"`
+	ReplayFile     string        `config:"REPLAY_FILE"`
+	ReplayInterval time.Duration `config:"REPLAY_INTERVAL"`
+}
@@ -0,0 +1,124 @@
+package control
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/damejeras/shootout/api"
+	"github.com/damejeras/shootout/internal/dashboard"
+	"github.com/damejeras/shootout/internal/infrastructure"
+	"github.com/damejeras/shootout/internal/shootout"
+)
+
+func (a *Arbiter) routes() http.Handler {
+	router := infrastructure.NewRouter()
+	router.Handle(http.MethodPost, "/register", a.handleRegistration)
+	router.Handle(http.MethodGet, "/events", a.handleEvents)
+	router.Handle(http.MethodGet, "/state", a.conceal(a.handleState))
+	router.Handle(http.MethodGet, "/competitors/{id}", a.conceal(a.handleCompetitor))
+	router.Handle(http.MethodDelete, "/competitors/{id}", a.handleRemoval)
+	router.Handle(http.MethodPost, "/start", a.handleTransition(a.state.Start))
+	router.Handle(http.MethodPost, "/pause", a.handleTransition(a.state.Pause))
+	router.Handle(http.MethodPost, "/resume", a.handleTransition(a.state.Resume))
+	router.Handle(http.MethodPost, "/abort", a.handleTransition(a.state.Abort))
+	router.Handle(http.MethodGet, "/openapi.yaml", handleOpenAPI)
+	router.Handle(http.MethodGet, "/*", dashboard.Handler().ServeHTTP)
+
+	return router
+}
+
+// conceal full state under fog of war until the game is finished, otherwise
+// competitors could see through it. Spectators still follow the game on event
+// stream, which gets full rounds.
+func (a *Arbiter) conceal(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if a.cfg.FogOfWar && a.state.Overview().Phase != shootout.PhaseFinished {
+			infrastructure.WriteError(w, http.StatusForbidden, infrastructure.ErrorCodeForbidden, "state is concealed by fog of war until the game is finished")
+			return
+		}
+
+		handler(w, r)
+	}
+}
+
+func (a *Arbiter) handleState(w http.ResponseWriter, r *http.Request) {
+	a.respond(w, http.StatusOK, a.state.Overview())
+}
+
+func (a *Arbiter) handleCompetitor(w http.ResponseWriter, r *http.Request) {
+	competitor, err := a.state.Competitor(infrastructure.PathParam(r, "id"))
+	if err != nil {
+		a.writeStateError(w, err)
+		return
+	}
+
+	a.respond(w, http.StatusOK, competitor)
+}
+
+func (a *Arbiter) handleRemoval(w http.ResponseWriter, r *http.Request) {
+	id := infrastructure.PathParam(r, "id")
+	if err := a.state.Remove(id); err != nil {
+		a.writeStateError(w, err)
+		return
+	}
+
+	a.logger.Printf("competitor %s removed from the game", id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *Arbiter) handleTransition(transition func() error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := transition(); err != nil {
+			a.writeStateError(w, err)
+			return
+		}
+
+		overview := a.state.Overview()
+		a.logger.Printf("shootout is %s", overview.Phase)
+		a.respond(w, http.StatusOK, overview)
+	}
+}
+
+func handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/yaml")
+	_, _ = w.Write(api.OpenAPI)
+}
+
+func (a *Arbiter) respond(w http.ResponseWriter, status int, body interface{}) {
+	if err := infrastructure.WriteJSON(w, status, body); err != nil {
+		a.logger.Printf("encode response: %v", err)
+	}
+}
+
+func (a *Arbiter) writeStateError(w http.ResponseWriter, err error) {
+	var validationErr *shootout.ValidationError
+	if errors.As(err, &validationErr) {
+		infrastructure.WriteErrorDetails(
+			w,
+			http.StatusBadRequest,
+			infrastructure.ErrorCodeValidation,
+			validationErr.Error(),
+			validationErr.Violations,
+		)
+		return
+	}
+
+	switch err {
+	case shootout.ErrUnknownCompetitor:
+		infrastructure.WriteError(w, http.StatusNotFound, infrastructure.ErrorCodeNotFound, err.Error())
+	case shootout.ErrInvalidRegistration:
+		infrastructure.WriteError(w, http.StatusBadRequest, infrastructure.ErrorCodeBadRequest, err.Error())
+	case shootout.ErrNotStarted,
+		shootout.ErrAlreadyStarted,
+		shootout.ErrFinished,
+		shootout.ErrNotEnoughCompetitors,
+		shootout.ErrNotRunning,
+		shootout.ErrNotPaused,
+		shootout.ErrEliminated,
+		shootout.ErrArenaFull:
+		infrastructure.WriteError(w, http.StatusConflict, infrastructure.ErrorCodeConflict, err.Error())
+	default:
+		a.logger.Printf("handle state change: %v", err)
+		infrastructure.WriteError(w, http.StatusInternalServerError, infrastructure.ErrorCodeInternal, "internal server error")
+	}
+}
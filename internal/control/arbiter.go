@@ -1,13 +1,14 @@
 package control
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"time"
 
 	"github.com/damejeras/shootout/internal/app"
@@ -19,26 +20,42 @@ import (
 
 const (
 	shutdownTimeout = time.Minute
+	defaultTick     = time.Second
 	arbiterPubSub   = "arbiter_events"
+	// spectatorPubSub carries full rounds under fog of war, when shooters
+	// receive their own views only.
+	spectatorPubSub = "spectator_events"
 )
 
 type registrationRequest struct {
-	Name   string `json:"name"`
-	Health int    `json:"health"`
-	Damage int    `json:"damage"`
+	Key      string   `json:"key,omitempty"`
+	Name     string   `json:"name"`
+	Health   int      `json:"health"`
+	Damage   int      `json:"damage"`
+	Accuracy int      `json:"accuracy"`
+	Armor    int      `json:"armor"`
+	Speed    int      `json:"speed"`
+	Team     string   `json:"team,omitempty"`
+	Weapon   string   `json:"weapon,omitempty"`
+	Items    []string `json:"items,omitempty"`
 }
 
 type Arbiter struct {
-	cfg           *app.ArbiterConfig
-	ctx           context.Context
-	cancel        context.CancelFunc
-	state         *shootout.State
-	logger        *log.Logger
-	redisClient   *redis.Client
-	lastRoundData json.RawMessage
+	cfg         *app.ArbiterConfig
+	ctx         context.Context
+	cancel      context.CancelFunc
+	state       *shootout.State
+	logger      *log.Logger
+	redisClient *redis.Client
+	hub         *infrastructure.Hub
+	// turns signals that every competitor acted in turn-based mode
+	turns chan struct{}
+	// pending registrations are confirmed with the next beat
+	pending     []*shootout.Competitor
+	pendingLock sync.Mutex
 }
 
-func NewArbiter(cfg *app.ArbiterConfig, state *shootout.State, logger *log.Logger, redisClient *redis.Client) *Arbiter {
+func NewArbiter(cfg *app.ArbiterConfig, state *shootout.State, logger *log.Logger, redisClient *redis.Client, hub *infrastructure.Hub) *Arbiter {
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
 
 	return &Arbiter{
@@ -48,12 +65,15 @@ func NewArbiter(cfg *app.ArbiterConfig, state *shootout.State, logger *log.Logge
 		state:       state,
 		logger:      logger,
 		redisClient: redisClient,
+		hub:         hub,
+		turns:       make(chan struct{}, 1),
 	}
 }
 
 func (a *Arbiter) Run() {
-	server := infrastructure.SingleEndpointHTTPServer(a.cfg.Port, "/register", a.handleRegistration)
-	ticker := time.Tick(time.Second)
+	server := infrastructure.HTTPServer(a.cfg.Port, a.routes())
+	ticker := time.NewTicker(a.tickInterval())
+	defer ticker.Stop()
 
 	go func() {
 		if err := server.ListenAndServe(); err != nil {
@@ -79,8 +99,10 @@ func (a *Arbiter) Run() {
 
 	for {
 		select {
-		case <-ticker:
-			a.beat()
+		case <-ticker.C:
+			a.nextRound(ticker)
+		case <-a.turns:
+			a.nextRound(ticker)
 		case <-a.ctx.Done():
 			shutdownCtx, cancel := context.WithTimeout(context.TODO(), shutdownTimeout)
 			if err := server.Shutdown(shutdownCtx); err != nil {
@@ -96,24 +118,61 @@ func (a *Arbiter) Run() {
 func (a *Arbiter) handleMessage(msg *redis.Message) {
 	var event infrastructure.Event
 	if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+		// nobody to answer to, a broken shooter does not stop the game
 		a.logger.Printf("unmarshal competitor event: %v", err)
-		a.cancel()
 		return
 	}
 
+	// state fails to handle competitor's event only because of the event
+	// itself, competitor learns why
 	err := a.state.Handle(&event)
-	if err != nil && err != shootout.ErrUnacceptablePayload {
-		a.logger.Printf("handle competitor event: %v", err)
-		a.cancel()
+	if err != nil {
+		a.logger.Printf("competitor event rejected: %v", err)
+	}
+
+	a.acknowledge(&event, err)
+
+	// rejected action counts as competitor's turn as well
+	if a.cfg.TurnBased && a.state.Ready() {
+		select {
+		case a.turns <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// tickInterval is the time between rounds, in turn-based mode it is the
+// deadline for competitors to act.
+func (a *Arbiter) tickInterval() time.Duration {
+	switch {
+	case a.cfg.TurnBased && a.cfg.TurnTimeout > 0:
+		return a.cfg.TurnTimeout
+	case a.cfg.TickInterval > 0:
+		return a.cfg.TickInterval
+	default:
+		return defaultTick
+	}
+}
+
+func (a *Arbiter) nextRound(ticker *time.Ticker) {
+	a.beat()
+
+	if !a.cfg.TurnBased {
 		return
 	}
 
-	if err != nil {
-		a.logger.Printf("competitor event: %v", err)
+	// turn that ended by deadline could be completed meanwhile
+	select {
+	case <-a.turns:
+	default:
 	}
+
+	ticker.Reset(a.tickInterval())
 }
 
 func (a *Arbiter) beat() {
+	a.confirmRegistrations()
+
 	event, err := a.state.Emit()
 	if err != nil {
 		a.logger.Printf("emit event: %v", err)
@@ -121,71 +180,97 @@ func (a *Arbiter) beat() {
 		return
 	}
 
-	if event.Type == infrastructure.EventRound {
-		defer func() { a.lastRoundData = event.Data }()
-	}
-
-	if bytes.Equal(event.Data, a.lastRoundData) {
-		a.logger.Printf("state did not change, not enough competitors")
+	if a.state.Stalled() {
+		a.logger.Printf("nobody acts, not enough competitors")
 		a.cancel()
 		return
 	}
 
-	payload, err := json.Marshal(event)
-	if err != nil {
-		a.logger.Printf("marshal event: %v", err)
-		a.cancel()
-		return
+	publish := a.broadcast
+	if a.cfg.FogOfWar && event.Type == infrastructure.EventRound {
+		publish = a.obscure
 	}
 
-	if err := a.redisClient.Publish(a.ctx, arbiterPubSub, payload).Err(); err != nil {
+	// shooters have their stats when round concludes the game
+	if event.Type == infrastructure.EventRound {
+		a.report()
+	}
+
+	if err := publish(event); err != nil {
 		a.logger.Printf("publish event: %v", err)
 		a.cancel()
 		return
 	}
+
+	a.spectate(event)
 }
 
-func (a *Arbiter) handleRegistration(w http.ResponseWriter, r *http.Request) {
-	var request registrationRequest
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		a.logger.Printf("decode request body: %v", err)
-		http.Error(w, "internal server error", http.StatusInternalServerError)
-		return
+func (a *Arbiter) broadcast(event *infrastructure.Event) error {
+	return a.publish(arbiterPubSub, event)
+}
+
+// obscure sends every competitor its own view of the round. Broadcast gets a
+// heartbeat, so shooters keep track of the arbiter and restarted ones rejoin.
+func (a *Arbiter) obscure(event *infrastructure.Event) error {
+	views, err := a.state.Views(event)
+	if err != nil {
+		return err
 	}
 
-	if request.Name == "" || request.Health == 0 || request.Damage == 0 {
-		http.Error(w, "bad request", http.StatusBadRequest)
-		return
+	for id, view := range views {
+		if err := a.publish(personalPubSub(id), view); err != nil {
+			return err
+		}
 	}
 
-	competitor := shootout.Competitor{
-		ID:     uuid.NewString(),
-		Name:   request.Name,
-		Health: request.Health,
-		Damage: request.Damage,
+	if err := a.publish(spectatorPubSub, event); err != nil {
+		return err
 	}
 
-	event, err := infrastructure.NewEvent(infrastructure.EventRegistration, &competitor)
+	heartbeat, err := infrastructure.NewEvent(infrastructure.EventHeartbeat, nil)
 	if err != nil {
-		a.logger.Printf("create registration event: %v", err)
-		http.Error(w, "internal server error", http.StatusInternalServerError)
-		return
+		return err
 	}
 
-	if err = a.state.Handle(event); err != nil {
-		if err == shootout.ErrFinished || err == shootout.ErrAlreadyStarted {
-			http.Error(w, "conflict", http.StatusConflict)
-			return
-		}
+	return a.publish(arbiterPubSub, heartbeat)
+}
 
-		a.logger.Printf("create registration event: %v", err)
-		http.Error(w, "internal server error", http.StatusInternalServerError)
+func (a *Arbiter) publish(channel string, event *infrastructure.Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshal event: %w", err)
+	}
+
+	return a.redisClient.Publish(a.ctx, channel, payload).Err()
+}
+
+func (a *Arbiter) handleRegistration(w http.ResponseWriter, r *http.Request) {
+	var request registrationRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		infrastructure.WriteError(w, http.StatusBadRequest, infrastructure.ErrorCodeBadRequest, "malformed request body")
 		return
 	}
 
-	if err := json.NewEncoder(w).Encode(competitor); err != nil {
-		a.logger.Printf("encode registration response: %v", err)
-		http.Error(w, "internal server error", http.StatusInternalServerError)
+	competitor, err := a.state.Register(&shootout.Registration{
+		Competitor: shootout.Competitor{
+			ID:       uuid.NewString(),
+			Name:     request.Name,
+			Health:   request.Health,
+			Damage:   request.Damage,
+			Accuracy: request.Accuracy,
+			Armor:    request.Armor,
+			Speed:    request.Speed,
+			Team:     request.Team,
+			Weapon:   request.Weapon,
+			Items:    request.Items,
+		},
+		Key: request.Key,
+	})
+	if err != nil {
+		a.writeStateError(w, err)
 		return
 	}
+
+	a.confirm(competitor)
+	a.respond(w, http.StatusOK, competitor)
 }
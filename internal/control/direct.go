@@ -0,0 +1,90 @@
+package control
+
+import (
+	"encoding/json"
+
+	"github.com/damejeras/shootout/internal/infrastructure"
+	"github.com/damejeras/shootout/internal/shootout"
+)
+
+// receipt tells competitor whether its action was accepted.
+type receipt struct {
+	Action *shootout.Action `json:"action,omitempty"`
+	Reason string           `json:"reason,omitempty"`
+}
+
+// personalPubSub carries events directed to a single competitor.
+func personalPubSub(id string) string {
+	return arbiterPubSub + ":" + id
+}
+
+// direct sends event to a single competitor. Competitor can miss it, so
+// failure is not fatal to the game.
+func (a *Arbiter) direct(id string, eventType infrastructure.EventType, data interface{}) {
+	event, err := infrastructure.NewEvent(eventType, data)
+	if err != nil {
+		a.logger.Printf("create %s event: %v", eventType, err)
+		return
+	}
+
+	if err := a.publish(personalPubSub(id), event); err != nil {
+		a.logger.Printf("publish %s event: %v", eventType, err)
+	}
+}
+
+// acknowledge competitor's action, rejected action comes with a reason.
+// Rejected registration is answered by competitor's ID, it has no action.
+func (a *Arbiter) acknowledge(event *infrastructure.Event, err error) {
+	if event.Type == infrastructure.EventRegistration {
+		var registration shootout.Registration
+		if err != nil && json.Unmarshal(event.Data, &registration) == nil && registration.ID != "" {
+			a.direct(registration.ID, infrastructure.EventRejection, &receipt{Reason: err.Error()})
+		}
+
+		return
+	}
+
+	var action shootout.Action
+	if json.Unmarshal(event.Data, &action) != nil || action.From == "" {
+		// nobody to answer to
+		return
+	}
+
+	if event.Type == infrastructure.EventShot {
+		action.Kind = shootout.ActionShoot
+	}
+
+	if err != nil {
+		a.direct(action.From, infrastructure.EventRejection, &receipt{Action: &action, Reason: err.Error()})
+		return
+	}
+
+	a.direct(action.From, infrastructure.EventAck, &receipt{Action: &action})
+}
+
+// confirm registration with the next beat, shooter subscribes to its
+// channel only after it learns its ID from registration response.
+func (a *Arbiter) confirm(competitor *shootout.Competitor) {
+	a.pendingLock.Lock()
+	defer a.pendingLock.Unlock()
+
+	a.pending = append(a.pending, competitor)
+}
+
+func (a *Arbiter) confirmRegistrations() {
+	a.pendingLock.Lock()
+	pending := a.pending
+	a.pending = nil
+	a.pendingLock.Unlock()
+
+	for _, competitor := range pending {
+		a.direct(competitor.ID, infrastructure.EventRegistered, competitor)
+	}
+}
+
+// report personal stats to every competitor.
+func (a *Arbiter) report() {
+	for id, stats := range a.state.Stats() {
+		a.direct(id, infrastructure.EventStats, stats)
+	}
+}
@@ -0,0 +1,176 @@
+package control
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/damejeras/shootout/internal/app"
+	"github.com/damejeras/shootout/internal/shootout"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+)
+
+const (
+	defaultPluginTimeout = 100 * time.Millisecond
+	// 64KiB each, 2MiB in total
+	defaultPluginMemoryPages = 32
+	pluginAlloc              = "alloc"
+	pluginChooseTarget       = "choose_target"
+)
+
+var (
+	ErrPluginExport = fmt.Errorf("plugin does not export required function")
+	ErrPluginMemory = fmt.Errorf("plugin memory access out of range")
+)
+
+// plugin is WebAssembly strategy. Module gets no host functions, so it can
+// only compute, and every call is limited in time and memory. It exports:
+//
+//	alloc(size i32) -> ptr i32, memory for the round
+//	choose_target(ptr i32, len i32) -> i64, action as ptr<<32|len, zero skips the round
+//
+// Round is passed in the same JSON as to the webhook, action is returned in
+// the same JSON as webhook responds with.
+type plugin struct {
+	runtime  wazero.Runtime
+	compiled wazero.CompiledModule
+	module   api.Module
+	timeout  time.Duration
+}
+
+func newPlugin(ctx context.Context, cfg *app.ShooterConfig) (*plugin, error) {
+	binary, err := os.ReadFile(cfg.Plugin)
+	if err != nil {
+		return nil, fmt.Errorf("read plugin: %w", err)
+	}
+
+	pages := uint32(defaultPluginMemoryPages)
+	if cfg.PluginMemoryPages > 0 {
+		pages = uint32(cfg.PluginMemoryPages)
+	}
+
+	p := &plugin{
+		runtime: wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfig().
+			WithMemoryLimitPages(pages).
+			WithCloseOnContextDone(true)),
+		timeout: cfg.PluginTimeout,
+	}
+
+	if p.timeout == 0 {
+		p.timeout = defaultPluginTimeout
+	}
+
+	p.compiled, err = p.runtime.CompileModule(ctx, binary)
+	if err != nil {
+		_ = p.runtime.Close(ctx)
+		return nil, fmt.Errorf("compile plugin: %w", err)
+	}
+
+	for _, name := range []string{pluginAlloc, pluginChooseTarget} {
+		if _, ok := p.compiled.ExportedFunctions()[name]; !ok {
+			_ = p.runtime.Close(ctx)
+			return nil, fmt.Errorf("%w %q", ErrPluginExport, name)
+		}
+	}
+
+	return p, nil
+}
+
+// choose asks plugin what to do this round, nil action skips the round.
+func (p *plugin) choose(ctx context.Context, id string, round *shootout.Round) (*shootout.Action, error) {
+	payload, err := json.Marshal(&webhookRequest{ID: id, Round: round})
+	if err != nil {
+		return nil, fmt.Errorf("marshal plugin request: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	module, err := p.instance(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	action, err := p.call(ctx, module, payload)
+	if err != nil {
+		// module can be left broken or closed on timeout, next round gets a new one
+		_ = module.Close(context.Background())
+		p.module = nil
+
+		return nil, err
+	}
+
+	if action == nil {
+		return nil, nil
+	}
+
+	if action.Kind == "" {
+		action.Kind = shootout.ActionShoot
+	}
+
+	// plugin acts only on behalf of its shooter
+	action.From = id
+
+	return action, nil
+}
+
+func (p *plugin) call(ctx context.Context, module api.Module, payload []byte) (*shootout.Action, error) {
+	allocated, err := module.ExportedFunction(pluginAlloc).Call(ctx, uint64(len(payload)))
+	if err != nil {
+		return nil, fmt.Errorf("call %s: %w", pluginAlloc, err)
+	}
+
+	ptr := uint32(allocated[0])
+	if !module.Memory().Write(ptr, payload) {
+		return nil, ErrPluginMemory
+	}
+
+	results, err := module.ExportedFunction(pluginChooseTarget).Call(ctx, uint64(ptr), uint64(len(payload)))
+	if err != nil {
+		return nil, fmt.Errorf("call %s: %w", pluginChooseTarget, err)
+	}
+
+	if results[0] == 0 {
+		return nil, nil
+	}
+
+	response, ok := module.Memory().Read(uint32(results[0]>>32), uint32(results[0]))
+	if !ok {
+		return nil, ErrPluginMemory
+	}
+
+	var action shootout.Action
+	if err := json.Unmarshal(response, &action); err != nil {
+		return nil, fmt.Errorf("decode plugin response: %w", err)
+	}
+
+	return &action, nil
+}
+
+// instance keeps module between rounds, so plugin can remember the game.
+func (p *plugin) instance(ctx context.Context) (api.Module, error) {
+	if p.module != nil {
+		return p.module, nil
+	}
+
+	module, err := p.runtime.InstantiateModule(ctx, p.compiled, wazero.NewModuleConfig().WithName(""))
+	if err != nil {
+		return nil, fmt.Errorf("instantiate plugin: %w", err)
+	}
+
+	if module.Memory() == nil {
+		_ = module.Close(context.Background())
+		return nil, fmt.Errorf("%w: plugin does not export memory", ErrPluginMemory)
+	}
+
+	p.module = module
+
+	return module, nil
+}
+
+func (p *plugin) close(ctx context.Context) error {
+	return p.runtime.Close(ctx)
+}
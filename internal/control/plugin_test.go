@@ -0,0 +1,116 @@
+package control
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/damejeras/shootout/internal/app"
+	"github.com/damejeras/shootout/internal/shootout"
+)
+
+// testPlugin counts calls of choose_target in exported global and behaves by
+// the first letter of shooter's ID, which is at offset 7 of the request:
+//
+//	(module
+//	  (memory (export "memory") 1)
+//	  (global (export "calls") (mut i32) (i32.const 0))
+//	  (func (export "alloc") (param i32) (result i32) i32.const 0)
+//	  (func (export "choose_target") (param i32 i32) (result i64)
+//	    global.get 0 i32.const 1 i32.add global.set 0
+//	    ;; "l" loops forever
+//	    local.get 0 i32.load8_u offset=7 i32.const 108 i32.eq
+//	    if loop br 0 end end
+//	    ;; "g" grows memory past the limit and traps once it fails
+//	    local.get 0 i32.load8_u offset=7 i32.const 103 i32.eq
+//	    if i32.const 1000 memory.grow i32.const -1 i32.eq if unreachable end end
+//	    ;; anything else skips the round
+//	    i64.const 0))
+var testPlugin = []byte{
+	0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00,
+	// types
+	0x01, 0x0c, 0x02, 0x60, 0x01, 0x7f, 0x01, 0x7f, 0x60, 0x02, 0x7f, 0x7f, 0x01, 0x7e,
+	// functions
+	0x03, 0x03, 0x02, 0x00, 0x01,
+	// memory
+	0x05, 0x03, 0x01, 0x00, 0x01,
+	// globals
+	0x06, 0x06, 0x01, 0x7f, 0x01, 0x41, 0x00, 0x0b,
+	// exports
+	0x07, 0x2a, 0x04,
+	0x06, 'm', 'e', 'm', 'o', 'r', 'y', 0x02, 0x00,
+	0x05, 'c', 'a', 'l', 'l', 's', 0x03, 0x00,
+	0x05, 'a', 'l', 'l', 'o', 'c', 0x00, 0x00,
+	0x0d, 'c', 'h', 'o', 'o', 's', 'e', '_', 't', 'a', 'r', 'g', 'e', 't', 0x00, 0x01,
+	// code
+	0x0a, 0x3b, 0x02,
+	0x04, 0x00, 0x41, 0x00, 0x0b,
+	0x34, 0x00,
+	0x23, 0x00, 0x41, 0x01, 0x6a, 0x24, 0x00,
+	0x20, 0x00, 0x2d, 0x00, 0x07, 0x41, 0xec, 0x00, 0x46,
+	0x04, 0x40, 0x03, 0x40, 0x0c, 0x00, 0x0b, 0x0b,
+	0x20, 0x00, 0x2d, 0x00, 0x07, 0x41, 0xe7, 0x00, 0x46,
+	0x04, 0x40, 0x41, 0xe8, 0x07, 0x40, 0x00, 0x41, 0x7f, 0x46, 0x04, 0x40, 0x00, 0x0b, 0x0b,
+	0x42, 0x00, 0x0b,
+}
+
+func TestPlugin(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plugin.wasm")
+	if err := os.WriteFile(path, testPlugin, 0o600); err != nil {
+		t.Fatalf("can not write plugin: %v", err)
+	}
+
+	ctx := context.Background()
+
+	p, err := newPlugin(ctx, &app.ShooterConfig{
+		Plugin:            path,
+		PluginTimeout:     50 * time.Millisecond,
+		PluginMemoryPages: 2,
+	})
+	if err != nil {
+		t.Fatalf("unexpected plugin err: %v", err)
+	}
+	defer p.close(ctx)
+
+	round := &shootout.Round{Competitors: map[string]*shootout.Competitor{}}
+
+	if action, err := p.choose(ctx, "skip", round); err != nil || action != nil {
+		t.Fatalf("expected round to be skipped, got %+v, err %v", action, err)
+	}
+
+	for _, id := range []string{"loop", "grow"} {
+		broken := p.module
+
+		started := time.Now()
+		if _, err := p.choose(ctx, id, round); err == nil {
+			t.Fatalf("expected err for %s", id)
+		}
+
+		if elapsed := time.Since(started); elapsed > time.Second {
+			t.Fatalf("%s should be stopped by timeout, took %v", id, elapsed)
+		}
+
+		if p.module != nil {
+			t.Fatalf("module should be dropped after %s", id)
+		}
+
+		if action, err := p.choose(ctx, "skip", round); err != nil || action != nil {
+			t.Fatalf("expected round to be skipped after %s, got %+v, err %v", id, action, err)
+		}
+
+		// fresh instance counts from the start
+		if p.module == broken {
+			t.Fatalf("expected new module after %s", id)
+		}
+
+		if calls := p.module.ExportedGlobal("calls").Get(); calls != 1 {
+			t.Fatalf("expected 1 call of new module after %s, got %d", id, calls)
+		}
+
+		if pages := p.module.Memory().Size() / 65536; pages > 2 {
+			t.Fatalf("expected memory within 2 pages, got %d", pages)
+		}
+	}
+}
@@ -15,16 +15,13 @@ import (
 	"github.com/damejeras/shootout/internal/app"
 	"github.com/damejeras/shootout/internal/infrastructure"
 	"github.com/damejeras/shootout/internal/shootout"
+	"github.com/damejeras/shootout/internal/strategy"
 	"github.com/go-redis/redis/v8"
 )
 
 const (
-	competitorPubSub = "competitor_events"
-)
-
-var (
-	ErrUnexpectedEvent = fmt.Errorf("unexpected event received")
-	ErrNoTarget        = fmt.Errorf("no target found")
+	competitorPubSub        = "competitor_events"
+	defaultHeartbeatTimeout = 2 * time.Second
 )
 
 type Shooter struct {
@@ -32,9 +29,13 @@ type Shooter struct {
 	cfg         *app.ShooterConfig
 	ctx         context.Context
 	cancel      context.CancelFunc
-	shotChan    chan *shootout.Shot
+	actionChan  chan *shootout.Action
+	plugin      *plugin
 	redisClient *redis.Client
+	sub         *redis.PubSub
 	logger      *log.Logger
+	// stats are reported by arbiter every round
+	stats *shootout.Stats
 }
 
 func NewShooter(cfg *app.ShooterConfig, redisClient *redis.Client, logger *log.Logger) *Shooter {
@@ -44,36 +45,47 @@ func NewShooter(cfg *app.ShooterConfig, redisClient *redis.Client, logger *log.L
 		cfg:         cfg,
 		ctx:         ctx,
 		cancel:      cancelFn,
-		shotChan:    make(chan *shootout.Shot),
+		actionChan:  make(chan *shootout.Action),
 		redisClient: redisClient,
 		logger:      logger,
 	}
 }
 
 func (s *Shooter) Run() {
-	go s.dispatchShots()
+	if s.cfg.Plugin != "" {
+		plugin, err := newPlugin(s.ctx, s.cfg)
+		if err != nil {
+			s.logger.Printf("load plugin: %v", err)
+			return
+		}
+		defer plugin.close(context.Background())
+
+		s.plugin = plugin
+	}
 
-	sub := s.redisClient.Subscribe(s.ctx, arbiterPubSub)
+	go s.dispatchActions()
+
+	s.sub = s.redisClient.Subscribe(s.ctx, arbiterPubSub)
 
 	for {
 		select {
 		// finished
 		case <-s.ctx.Done():
-			if err := sub.Close(); err != nil {
+			if err := s.sub.Close(); err != nil {
 				s.logger.Printf("close arbiter pub/sub: %v", err)
 			}
 
-			close(s.shotChan)
+			close(s.actionChan)
 
 			return
-		// we expect to receive a message every second
-		case msg := <-sub.Channel():
+		// we expect to receive a message every tick
+		case msg := <-s.sub.Channel():
 			if err := s.handleArbiterMessage(msg); err != nil {
 				s.logger.Printf("handle message from arbiter: %v", err)
 				s.cancel()
 			}
 		// communication is lost
-		case <-time.After(time.Second * 2):
+		case <-time.After(s.heartbeatTimeout()):
 			s.logger.Printf("no heartbeat")
 			s.cancel()
 		}
@@ -94,8 +106,9 @@ func (s *Shooter) handleArbiterMessage(msg *redis.Message) error {
 
 		return nil
 	case infrastructure.EventRound:
+		// shooter restarted mid-game, take control of our competitor again
 		if s.ID == "" {
-			return ErrUnexpectedEvent
+			return s.register()
 		}
 
 		var round shootout.Round
@@ -103,36 +116,122 @@ func (s *Shooter) handleArbiterMessage(msg *redis.Message) error {
 			return fmt.Errorf("unmarshal competitors: %w", err)
 		}
 
-		_, ok := round.Competitors[s.ID]
-		if len(round.Competitors) == 1 && ok {
-			log.Println("🏆 Winner 🏆")
-			s.cancel()
+		if round.Result != nil {
+			s.conclude(round.Result)
 			return nil
 		}
 
+		me, ok := round.Competitors[s.ID]
 		if !ok {
 			log.Println("💀 Dead 💀")
 			s.cancel()
 			return nil
 		}
 
-		for target := range round.Competitors {
-			if target != s.ID {
-				s.shotChan <- &shootout.Shot{
-					From: s.ID,
-					To:   target,
-				}
+		// stunned competitor can not do anything
+		for _, effect := range me.Effects {
+			if effect.Kind == shootout.EffectStunned {
+				return nil
+			}
+		}
 
+		if s.plugin != nil || s.cfg.Webhook != "" {
+			action, err := s.external(&round)
+			if err != nil {
+				// bot's mistake costs it a round, not the game
+				s.logger.Printf("external strategy: %v", err)
 				return nil
 			}
+
+			if action != nil {
+				s.actionChan <- action
+			}
+
+			return nil
+		}
+
+		action, err := strategy.Builtin(s.ID, s.cfg.Health, &round)
+		if err != nil {
+			return err
+		}
+
+		s.actionChan <- action
+
+		return nil
+	case infrastructure.EventAck:
+		return nil
+	case infrastructure.EventRejection:
+		var rejection receipt
+		if err := json.Unmarshal(event.Data, &rejection); err != nil {
+			return fmt.Errorf("unmarshal rejection: %w", err)
+		}
+
+		if rejection.Action == nil {
+			s.logger.Printf("registration rejected: %s", rejection.Reason)
+			return nil
 		}
 
-		return ErrNoTarget
+		s.logger.Printf("%s rejected: %s", rejection.Action.Kind, rejection.Reason)
+
+		return nil
+	case infrastructure.EventRegistered:
+		s.logger.Printf("registered as %s", s.ID)
+		return nil
+	case infrastructure.EventStats:
+		var stats shootout.Stats
+		if err := json.Unmarshal(event.Data, &stats); err != nil {
+			return fmt.Errorf("unmarshal stats: %w", err)
+		}
+
+		s.stats = &stats
+
+		return nil
 	default:
 		return fmt.Errorf("unknown event %q received", event.Type)
 	}
 }
 
+// external asks plugin or webhook what to do this round.
+func (s *Shooter) external(round *shootout.Round) (*shootout.Action, error) {
+	if s.plugin != nil {
+		return s.plugin.choose(s.ctx, s.ID, round)
+	}
+
+	return s.webhook(round)
+}
+
+func (s *Shooter) heartbeatTimeout() time.Duration {
+	if s.cfg.HeartbeatTimeout > 0 {
+		return s.cfg.HeartbeatTimeout
+	}
+
+	return defaultHeartbeatTimeout
+}
+
+func (s *Shooter) conclude(result *shootout.Result) {
+	switch {
+	case result.Winner == s.ID:
+		log.Println("🏆 Winner 🏆")
+	case result.Team != "" && result.Team == s.cfg.Team:
+		log.Println("🏆 Team won 🏆")
+	case result.Decision == shootout.DecisionAborted:
+		log.Println("🛑 Aborted 🛑")
+	case result.Decision == shootout.DecisionSuddenDeath && result.Winner == "" && result.Team == "":
+		log.Println("🤝 Draw 🤝")
+	case result.Decision == shootout.DecisionInsufficientPlayers:
+		log.Println("🚫 Not enough players showed up 🚫")
+	default:
+		log.Println("💀 Dead 💀")
+	}
+
+	if s.stats != nil {
+		log.Printf("shots %d, hits %d, dealt %d, taken %d, kills %d",
+			s.stats.Shots, s.stats.Hits, s.stats.Dealt, s.stats.Taken, s.stats.Kills)
+	}
+
+	s.cancel()
+}
+
 func (s *Shooter) register() error {
 	arbiterURL, err := url.Parse(s.cfg.ArbiterAddr)
 	if err != nil {
@@ -142,9 +241,16 @@ func (s *Shooter) register() error {
 	arbiterURL.Path = "/register"
 
 	payload, err := json.Marshal(&registrationRequest{
-		Name:   s.cfg.Name,
-		Health: s.cfg.Health,
-		Damage: s.cfg.Damage,
+		Key:      s.cfg.Key,
+		Name:     s.cfg.Name,
+		Health:   s.cfg.Health,
+		Damage:   s.cfg.Damage,
+		Accuracy: s.cfg.Accuracy,
+		Armor:    s.cfg.Armor,
+		Speed:    s.cfg.Speed,
+		Team:     s.cfg.Team,
+		Weapon:   s.cfg.Weapon,
+		Items:    s.cfg.Items,
 	})
 	if err != nil {
 		return fmt.Errorf("marshal registration request body: %w", err)
@@ -159,40 +265,50 @@ func (s *Shooter) register() error {
 	if err != nil {
 		return fmt.Errorf("send registration request: %w", err)
 	}
+	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected registration response code %d", resp.StatusCode)
+		var response infrastructure.ErrorResponse
+		if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+			return fmt.Errorf("unexpected registration response code %d", resp.StatusCode)
+		}
+
+		return fmt.Errorf("registration rejected: %s", response.Error.Message)
 	}
 
 	var competitor shootout.Competitor
 	if err := json.NewDecoder(resp.Body).Decode(&competitor); err != nil {
 		return fmt.Errorf("decode registration response: %w", err)
 	}
-	defer resp.Body.Close()
 
 	s.ID = competitor.ID
 
+	// confirmation and rounds under fog of war are directed to competitor
+	if err := s.sub.Subscribe(s.ctx, personalPubSub(s.ID)); err != nil {
+		return fmt.Errorf("subscribe to personal events: %w", err)
+	}
+
 	return nil
 }
 
-func (s *Shooter) dispatchShots() {
-	for shot := range s.shotChan {
-		event, err := infrastructure.NewEvent(infrastructure.EventShot, shot)
+func (s *Shooter) dispatchActions() {
+	for action := range s.actionChan {
+		event, err := infrastructure.NewEvent(infrastructure.EventAction, action)
 		if err != nil {
-			s.logger.Printf("create shot event: %v", err)
+			s.logger.Printf("create action event: %v", err)
 			s.cancel()
 			return
 		}
 
 		payload, err := json.Marshal(event)
 		if err != nil {
-			s.logger.Printf("marshal shot event: %v", err)
+			s.logger.Printf("marshal action event: %v", err)
 			s.cancel()
 			return
 		}
 
 		if err := s.redisClient.Publish(s.ctx, competitorPubSub, payload).Err(); err != nil {
-			s.logger.Printf("publish shot event: %v", err)
+			s.logger.Printf("publish action event: %v", err)
 			s.cancel()
 			return
 		}
@@ -0,0 +1,97 @@
+package control
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/damejeras/shootout/internal/infrastructure"
+	"github.com/damejeras/shootout/internal/shootout"
+)
+
+// spectate forwards emitted event to spectators. Shots resolved during the
+// round are announced separately before the round itself.
+func (a *Arbiter) spectate(event *infrastructure.Event) {
+	if event.Type == infrastructure.EventRound {
+		var round shootout.Round
+		if err := json.Unmarshal(event.Data, &round); err != nil {
+			a.logger.Printf("unmarshal round for spectators: %v", err)
+			return
+		}
+
+		for _, hit := range round.Feed {
+			eventType := infrastructure.EventType(infrastructure.EventHit)
+			switch {
+			case hit.Killed:
+				eventType = infrastructure.EventKill
+			case hit.Outcome == shootout.OutcomeMiss:
+				eventType = infrastructure.EventMiss
+			}
+
+			notification, err := infrastructure.NewEvent(eventType, hit)
+			if err != nil {
+				a.logger.Printf("create %s notification: %v", eventType, err)
+				return
+			}
+
+			a.hub.Publish(notification)
+		}
+	}
+
+	a.hub.Publish(event)
+}
+
+func (a *Arbiter) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		infrastructure.WriteError(w, http.StatusInternalServerError, infrastructure.ErrorCodeInternal, "streaming unsupported")
+		return
+	}
+
+	events, unsubscribe := a.hub.Subscribe()
+	defer unsubscribe()
+
+	snapshot, err := a.state.Snapshot()
+	if err != nil {
+		a.logger.Printf("create spectator snapshot: %v", err)
+		infrastructure.WriteError(w, http.StatusInternalServerError, infrastructure.ErrorCodeInternal, "internal server error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	if err := writeServerSentEvent(w, snapshot); err != nil {
+		a.logger.Printf("write spectator snapshot: %v", err)
+		return
+	}
+	flusher.Flush()
+
+	for {
+		select {
+		case event := <-events:
+			if err := writeServerSentEvent(w, event); err != nil {
+				a.logger.Printf("write spectator event: %v", err)
+				return
+			}
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		case <-a.ctx.Done():
+			return
+		}
+	}
+}
+
+func writeServerSentEvent(w io.Writer, event *infrastructure.Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshal event: %w", err)
+	}
+
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, payload)
+
+	return err
+}
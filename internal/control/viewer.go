@@ -0,0 +1,213 @@
+package control
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/damejeras/shootout/internal/app"
+	"github.com/damejeras/shootout/internal/infrastructure"
+	"github.com/damejeras/shootout/internal/tui"
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	defaultReplayInterval = 500 * time.Millisecond
+	maxServerSentEvent    = 1 << 20
+)
+
+// Viewer renders a game in the terminal. Game is followed through arbiter
+// event stream, arbiter pub/sub or replayed from a recording.
+type Viewer struct {
+	cfg         *app.ViewerConfig
+	ctx         context.Context
+	cancel      context.CancelFunc
+	board       *tui.Board
+	recording   *json.Encoder
+	redisClient *redis.Client
+	logger      *log.Logger
+}
+
+func NewViewer(cfg *app.ViewerConfig, board *tui.Board, redisClient *redis.Client, logger *log.Logger) *Viewer {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+
+	return &Viewer{
+		cfg:         cfg,
+		ctx:         ctx,
+		cancel:      cancel,
+		board:       board,
+		redisClient: redisClient,
+		logger:      logger,
+	}
+}
+
+func (v *Viewer) Run() {
+	defer v.cancel()
+
+	if v.cfg.RecordFile != "" && v.cfg.ReplayFile == "" {
+		file, err := os.Create(v.cfg.RecordFile)
+		if err != nil {
+			v.logger.Printf("create recording: %v", err)
+			return
+		}
+		defer file.Close()
+
+		v.recording = json.NewEncoder(file)
+	}
+
+	if err := v.board.Render(os.Stdout); err != nil {
+		v.logger.Printf("render board: %v", err)
+		return
+	}
+
+	var err error
+	switch {
+	case v.cfg.ReplayFile != "":
+		err = v.replay()
+	case v.cfg.ArbiterAddr != "":
+		err = v.stream()
+	default:
+		err = v.subscribe()
+	}
+
+	if err != nil && v.ctx.Err() == nil {
+		v.logger.Printf("follow game: %v", err)
+	}
+}
+
+func (v *Viewer) show(event *infrastructure.Event) error {
+	if v.recording != nil {
+		if err := v.recording.Encode(event); err != nil {
+			return fmt.Errorf("record event: %w", err)
+		}
+	}
+
+	if err := v.board.Apply(event); err != nil {
+		return fmt.Errorf("apply event: %w", err)
+	}
+
+	if err := v.board.Render(os.Stdout); err != nil {
+		return fmt.Errorf("render board: %w", err)
+	}
+
+	if v.board.Finished() {
+		v.cancel()
+	}
+
+	return nil
+}
+
+func (v *Viewer) replay() error {
+	file, err := os.Open(v.cfg.ReplayFile)
+	if err != nil {
+		return fmt.Errorf("open recording: %w", err)
+	}
+	defer file.Close()
+
+	interval := v.cfg.ReplayInterval
+	if interval == 0 {
+		interval = defaultReplayInterval
+	}
+
+	decoder := json.NewDecoder(file)
+	for v.ctx.Err() == nil {
+		var event infrastructure.Event
+		if err := decoder.Decode(&event); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+
+			return fmt.Errorf("decode recorded event: %w", err)
+		}
+
+		if event.Type != infrastructure.EventRound {
+			continue
+		}
+
+		if err := v.show(&event); err != nil {
+			return err
+		}
+
+		select {
+		case <-time.After(interval):
+		case <-v.ctx.Done():
+		}
+	}
+
+	return nil
+}
+
+func (v *Viewer) stream() error {
+	eventsURL, err := url.Parse(v.cfg.ArbiterAddr)
+	if err != nil {
+		return fmt.Errorf("parse arbiter url: %w", err)
+	}
+
+	eventsURL.Path = "/events"
+
+	req, err := http.NewRequestWithContext(v.ctx, http.MethodGet, eventsURL.String(), nil)
+	if err != nil {
+		return fmt.Errorf("create events request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("send events request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected events response code %d", resp.StatusCode)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(nil, maxServerSentEvent)
+
+	for scanner.Scan() {
+		data := strings.TrimPrefix(scanner.Text(), "data: ")
+		if data == scanner.Text() {
+			continue
+		}
+
+		var event infrastructure.Event
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			return fmt.Errorf("unmarshal event: %w", err)
+		}
+
+		if err := v.show(&event); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
+
+func (v *Viewer) subscribe() error {
+	sub := v.redisClient.Subscribe(v.ctx, arbiterPubSub, spectatorPubSub)
+	defer sub.Close()
+
+	for {
+		select {
+		case msg := <-sub.Channel():
+			var event infrastructure.Event
+			if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+				return fmt.Errorf("unmarshal event: %w", err)
+			}
+
+			if err := v.show(&event); err != nil {
+				return err
+			}
+		case <-v.ctx.Done():
+			return nil
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package control
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/damejeras/shootout/internal/shootout"
+)
+
+const defaultWebhookTimeout = 500 * time.Millisecond
+
+// webhookRequest is posted to the bot every round.
+type webhookRequest struct {
+	ID    string          `json:"id"`
+	Round *shootout.Round `json:"round"`
+}
+
+// webhook asks the bot what to do this round. Bot responds with an action,
+// which is a shot when kind is omitted, or with no content to skip the round.
+func (s *Shooter) webhook(round *shootout.Round) (*shootout.Action, error) {
+	payload, err := json.Marshal(&webhookRequest{ID: s.ID, Round: round})
+	if err != nil {
+		return nil, fmt.Errorf("marshal webhook request body: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, s.webhookTimeout())
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.cfg.Webhook, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("create webhook request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("send webhook request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNoContent:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unexpected webhook response code %d", resp.StatusCode)
+	}
+
+	var action shootout.Action
+	if err := json.NewDecoder(resp.Body).Decode(&action); err != nil {
+		return nil, fmt.Errorf("decode webhook response: %w", err)
+	}
+
+	if action.Kind == "" {
+		action.Kind = shootout.ActionShoot
+	}
+
+	// bot acts only on behalf of its shooter
+	action.From = s.ID
+
+	return &action, nil
+}
+
+func (s *Shooter) webhookTimeout() time.Duration {
+	if s.cfg.WebhookTimeout > 0 {
+		return s.cfg.WebhookTimeout
+	}
+
+	return defaultWebhookTimeout
+}
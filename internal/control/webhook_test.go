@@ -0,0 +1,239 @@
+package control
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/damejeras/shootout/internal/app"
+	"github.com/damejeras/shootout/internal/infrastructure"
+	"github.com/damejeras/shootout/internal/shootout"
+	"github.com/go-redis/redis/v8"
+)
+
+func TestWebhook(t *testing.T) {
+	round := &shootout.Round{Competitors: map[string]*shootout.Competitor{
+		"test_1": {ID: "test_1", Name: "Test1", Health: 3, Damage: 1},
+		"test_2": {ID: "test_2", Name: "Test2", Health: 3, Damage: 1},
+	}}
+
+	for _, tc := range []struct {
+		name    string
+		handler http.HandlerFunc
+		action  *shootout.Action
+		err     bool
+	}{
+		{
+			name: "shot",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"to": "test_2"}`))
+			},
+			action: &shootout.Action{Kind: shootout.ActionShoot, From: "test_1", To: "test_2"},
+		},
+		{
+			name: "action on behalf of another shooter",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"kind": "cover", "from": "test_2"}`))
+			},
+			action: &shootout.Action{Kind: shootout.ActionCover, From: "test_1"},
+		},
+		{
+			name: "skip",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			},
+		},
+		{
+			name: "unexpected status",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+			err: true,
+		},
+		{
+			name: "malformed response",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"to": `))
+			},
+			err: true,
+		},
+		{
+			name: "timeout",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				select {
+				case <-r.Context().Done():
+				case <-time.After(time.Second):
+				}
+			},
+			err: true,
+		},
+	} {
+		var request webhookRequest
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+				t.Errorf("%s: can not decode webhook request: %v", tc.name, err)
+			}
+
+			tc.handler(w, r)
+		}))
+
+		shooter := webhookShooter("test_1", server.URL)
+
+		started := time.Now()
+		action, err := shooter.webhook(round)
+		server.Close()
+
+		if tc.err != (err != nil) {
+			t.Fatalf("%s: expected err %v, got %v", tc.name, tc.err, err)
+		}
+
+		if elapsed := time.Since(started); elapsed > 500*time.Millisecond {
+			t.Fatalf("%s: webhook should give up after timeout, took %v", tc.name, elapsed)
+		}
+
+		if request.ID != "test_1" || request.Round == nil || len(request.Round.Competitors) != 2 {
+			t.Fatalf("%s: unexpected webhook request %+v", tc.name, request)
+		}
+
+		switch {
+		case tc.action == nil && action != nil:
+			t.Fatalf("%s: expected no action, got %+v", tc.name, action)
+		case tc.action != nil && (action == nil || *action != *tc.action):
+			t.Fatalf("%s: expected action %+v, got %+v", tc.name, tc.action, action)
+		}
+	}
+}
+
+func TestWebhookSkip(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	cfg := &app.ArbiterConfig{Competitors: 2, MaxRounds: 3}
+	state := shootout.NewState(cfg)
+	hub := infrastructure.NewHub()
+	redisClient := redis.NewClient(&redis.Options{Addr: fakeRedis(t)})
+	defer redisClient.Close()
+
+	arbiter := NewArbiter(cfg, state, log.New(io.Discard, "", 0), redisClient, hub)
+	defer arbiter.cancel()
+
+	var shooters []*Shooter
+	for _, id := range []string{"test_1", "test_2"} {
+		competitor := shootout.Competitor{ID: id, Name: strings.ToUpper(id), Health: 3, Damage: 1}
+		if _, err := state.Register(&shootout.Registration{Competitor: competitor}); err != nil {
+			t.Fatalf("unexpected registration err: %v", err)
+		}
+
+		shooters = append(shooters, webhookShooter(id, server.URL))
+	}
+
+	events, unsubscribe := hub.Subscribe()
+	defer unsubscribe()
+
+	for beat := 0; beat < 10; beat++ {
+		arbiter.beat()
+
+		for len(events) > 0 {
+			event := <-events
+			if event.Type != infrastructure.EventRound {
+				continue
+			}
+
+			var round shootout.Round
+			if err := json.Unmarshal(event.Data, &round); err != nil {
+				t.Fatalf("can not unmarshal round event: %v", err)
+			}
+
+			if round.Result != nil {
+				if round.Result.Decision != shootout.DecisionSuddenDeath {
+					t.Fatalf("game should be decided by sudden death, got %+v", round.Result)
+				}
+
+				return
+			}
+
+			if arbiter.ctx.Err() != nil {
+				t.Fatalf("arbiter should survive identical rounds, stopped in round %d", state.Overview().Round)
+			}
+
+			for _, shooter := range shooters {
+				if action, err := shooter.webhook(&round); err != nil || action != nil {
+					t.Fatalf("expected round to be skipped, got %+v, err %v", action, err)
+				}
+			}
+		}
+	}
+
+	t.Fatalf("game should be over after round limit, got %+v", state.Overview())
+}
+
+func webhookShooter(id, webhook string) *Shooter {
+	return &Shooter{
+		ID:  id,
+		ctx: context.Background(),
+		cfg: &app.ShooterConfig{
+			Webhook:        webhook,
+			WebhookTimeout: 50 * time.Millisecond,
+		},
+	}
+}
+
+// fakeRedis answers every command with zero, which is enough for the arbiter
+// to publish events nobody listens to.
+func fakeRedis(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("can not listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer conn.Close()
+
+				reader := bufio.NewReader(conn)
+				for {
+					// command is an array of bulk strings, each takes two lines
+					header, err := reader.ReadString('\n')
+					if err != nil {
+						return
+					}
+
+					var args int
+					if _, err := fmt.Sscanf(header, "*%d", &args); err != nil {
+						return
+					}
+
+					for i := 0; i < 2*args; i++ {
+						if _, err := reader.ReadString('\n'); err != nil {
+							return
+						}
+					}
+
+					if _, err := conn.Write([]byte(":0\r\n")); err != nil {
+						return
+					}
+				}
+			}()
+		}
+	}()
+
+	return listener.Addr().String()
+}
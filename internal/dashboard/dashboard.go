@@ -0,0 +1,22 @@
+package dashboard
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+//go:embed static
+var static embed.FS
+
+// Handler serves the spectator dashboard. Dashboard expects arbiter event
+// stream to be available at /events.
+func Handler() http.Handler {
+	assets, err := fs.Sub(static, "static")
+	if err != nil {
+		// static directory is embedded at compile time, this can not happen
+		panic(err)
+	}
+
+	return http.FileServer(http.FS(assets))
+}
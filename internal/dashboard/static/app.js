@@ -0,0 +1,252 @@
+"use strict";
+
+const feedLength = 20;
+
+const arena = document.getElementById("arena");
+const feed = document.getElementById("feed");
+const podium = document.getElementById("podium");
+const status = document.getElementById("status");
+
+// competitors seen during the game, kept after elimination
+const competitors = new Map();
+// competitor IDs in order of elimination
+const eliminated = [];
+let round = 0;
+
+function card(competitor) {
+    let known = competitors.get(competitor.id);
+    if (known) {
+        return known;
+    }
+
+    const element = document.createElement("div");
+    element.className = "competitor";
+    element.innerHTML = `<h3></h3><div class="health"><div></div></div><div class="stats"></div>`;
+    element.querySelector("h3").textContent = competitor.team ? `${competitor.name} [${competitor.team}]` : competitor.name;
+    arena.appendChild(element);
+
+    known = {id: competitor.id, name: competitor.name, maxHealth: competitor.health, element: element};
+    competitors.set(competitor.id, known);
+
+    return known;
+}
+
+function render(competitor) {
+    const known = card(competitor);
+    const ratio = Math.max(competitor.health, 0) / known.maxHealth;
+    const bar = known.element.querySelector(".health");
+
+    bar.firstElementChild.style.width = `${ratio * 100}%`;
+    bar.classList.toggle("low", ratio <= .5);
+    bar.classList.toggle("critical", ratio <= .25);
+    known.element.querySelector(".stats").textContent =
+        `❤️ ${Math.max(competitor.health, 0)}/${known.maxHealth} · 🔫 ${competitor.damage}` +
+        ` · 🎯 ${competitor.accuracy || 0} · 🛡️ ${competitor.armor || 0} · 💨 ${competitor.speed || 0}` +
+        weapon(competitor) +
+        (competitor.inCover ? " · in cover" : "") +
+        (competitor.aiming ? " · aiming" : "") +
+        (competitor.position ? ` · 📍 ${competitor.position.x},${competitor.position.y}` : "") +
+        (competitor.effects || []).map((effect) => ` · ${effect.kind} ${effect.rounds}`).join("");
+}
+
+function weapon(competitor) {
+    if (!competitor.weapon) {
+        return "";
+    }
+
+    const magazine = competitor.reloading ? `reloading ${competitor.reloading}` : `${competitor.magazine || 0}/${competitor.ammo || 0}`;
+
+    return ` · ${competitor.weapon} ${magazine}`;
+}
+
+function animate(id, className) {
+    const known = competitors.get(id);
+    if (!known) {
+        return;
+    }
+
+    known.element.classList.remove(className);
+    // restart animation
+    void known.element.offsetWidth;
+    known.element.classList.add(className);
+}
+
+function name(id) {
+    const known = competitors.get(id);
+
+    return known ? known.name : id;
+}
+
+function announce(text, className) {
+    const item = document.createElement("li");
+    item.textContent = text;
+    if (className) {
+        item.className = className;
+    }
+
+    feed.prepend(item);
+    while (feed.children.length > feedLength) {
+        feed.lastElementChild.remove();
+    }
+}
+
+function eliminate(id) {
+    const known = competitors.get(id);
+    if (!known || eliminated.includes(id)) {
+        return;
+    }
+
+    eliminated.push(id);
+    known.element.classList.add("eliminated");
+    known.element.querySelector(".health").firstElementChild.style.width = "0";
+}
+
+function showPodium(survivors) {
+    const list = podium.querySelector("ol");
+    list.innerHTML = "";
+
+    for (const id of survivors.concat(eliminated.slice().reverse())) {
+        const item = document.createElement("li");
+        item.textContent = name(id);
+        list.appendChild(item);
+    }
+
+    podium.hidden = false;
+}
+
+function onRound(data) {
+    round++;
+    status.textContent = `round ${round}`;
+
+    const alive = Object.values(data.Competitors || {});
+    for (const competitor of alive) {
+        render(competitor);
+    }
+
+    for (const id of competitors.keys()) {
+        if (!(data.Competitors || {})[id]) {
+            eliminate(id);
+        }
+    }
+
+    if (data.result && data.result.decision === "aborted") {
+        status.textContent = `aborted after ${round} rounds`;
+        return;
+    }
+
+    if (data.result && data.result.decision === "insufficient_players") {
+        status.textContent = "not enough players showed up";
+        return;
+    }
+
+    if (data.result && data.result.decision === "sudden_death") {
+        const winner = data.result.winner ? name(data.result.winner) : data.result.team;
+        status.textContent = winner
+            ? `${winner} won by sudden death (${data.result.suddenDeath}) after ${round} rounds`
+            : `draw by sudden death (${data.result.suddenDeath}) after ${round} rounds`;
+        showPodium(alive.map((competitor) => competitor.id));
+        return;
+    }
+
+    if (data.result && data.result.decision === "scripted" && data.result.winner) {
+        status.textContent = `${name(data.result.winner)} won by the rules after ${round} rounds`;
+        showPodium([data.result.winner]);
+        return;
+    }
+
+    if (data.result && data.result.team) {
+        status.textContent = `team ${data.result.team} won after ${round} rounds`;
+        showPodium(alive.map((competitor) => competitor.id));
+        return;
+    }
+
+    if (data.result || alive.length <= 1) {
+        status.textContent = `finished after ${round} rounds`;
+        showPodium(alive.map((competitor) => competitor.id));
+    }
+}
+
+function onHit(hit) {
+    if (hit.outcome === "bleeding") {
+        animate(hit.to, "hit");
+        announce(`🩸 ${name(hit.to)} bleeds for ${hit.damage}`);
+        return;
+    }
+
+    if (hit.outcome === "sudden_death") {
+        animate(hit.to, "hit");
+        return;
+    }
+
+    if (hit.outcome === "scripted") {
+        animate(hit.to, "hit");
+        announce(`📜 ${name(hit.to)} loses ${hit.damage} by the rules`);
+        return;
+    }
+
+    if (hit.outcome === "penalty") {
+        animate(hit.to, "hit");
+        announce(`⚖️ ${name(hit.to)} pays ${hit.damage} for friendly fire`);
+        return;
+    }
+
+    animate(hit.from, "shooting");
+    animate(hit.to, "hit");
+    if (hit.outcome === "critical") {
+        announce(`💥 ${name(hit.from)} critically hit ${name(hit.to)} for ${hit.damage}`);
+    } else {
+        announce(`🔫 ${name(hit.from)} hit ${name(hit.to)} for ${hit.damage}`);
+    }
+}
+
+function onMiss(hit) {
+    animate(hit.from, "shooting");
+    announce(`💨 ${name(hit.from)} missed ${name(hit.to)}`);
+}
+
+function onKill(hit) {
+    eliminate(hit.to);
+
+    if (hit.outcome === "bleeding") {
+        announce(`🩸 ${name(hit.to)} bled out from ${name(hit.from)}'s wound`, "kill");
+        return;
+    }
+
+    if (hit.outcome === "penalty") {
+        announce(`⚖️ ${name(hit.to)} paid for friendly fire with life`, "kill");
+        return;
+    }
+
+    if (hit.outcome === "sudden_death") {
+        announce(`☠️ ${name(hit.to)} did not survive sudden death`, "kill");
+        return;
+    }
+
+    if (hit.outcome === "scripted") {
+        announce(`📜 ${name(hit.to)} was eliminated by the rules`, "kill");
+        return;
+    }
+
+    animate(hit.from, "shooting");
+    announce(`💀 ${name(hit.from)} killed ${name(hit.to)}`, "kill");
+}
+
+const source = new EventSource("events");
+
+source.addEventListener("heartbeat", (message) => {
+    const heartbeat = JSON.parse(message.data).data;
+    if (heartbeat && heartbeat.paused) {
+        status.textContent = `paused at round ${round}`;
+    } else if (round === 0) {
+        status.textContent = "waiting for competitors…";
+    }
+});
+source.addEventListener("round", (message) => onRound(JSON.parse(message.data).data));
+source.addEventListener("hit", (message) => onHit(JSON.parse(message.data).data));
+source.addEventListener("miss", (message) => onMiss(JSON.parse(message.data).data));
+source.addEventListener("kill", (message) => onKill(JSON.parse(message.data).data));
+source.onerror = () => {
+    if (podium.hidden) {
+        status.textContent = "connection lost, reconnecting…";
+    }
+};
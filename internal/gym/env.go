@@ -0,0 +1,179 @@
+package gym
+
+import (
+	"fmt"
+
+	"github.com/damejeras/shootout/internal/app"
+	"github.com/damejeras/shootout/internal/shootout"
+	"github.com/damejeras/shootout/internal/simulation"
+	"github.com/damejeras/shootout/internal/strategy"
+)
+
+const (
+	agentID = "agent"
+	// survivalReward is given for every round agent survives.
+	survivalReward = 1
+)
+
+var (
+	ErrNotReset    = fmt.Errorf("environment is not reset")
+	ErrEpisodeOver = fmt.Errorf("episode is over, environment has to be reset")
+	ErrNoOpponents = fmt.Errorf("at least one opponent is required")
+	ErrTooManyEnvs = fmt.Errorf("too many environments, close some of them")
+)
+
+// Config of the environment. Agent is the competitor controlled by the
+// policy being trained, opponents play built-in strategy.
+type Config struct {
+	Agent     shootout.Competitor   `json:"agent"`
+	Opponents []shootout.Competitor `json:"opponents"`
+}
+
+// Observation is the round as seen by the agent, it is the same request
+// bots receive.
+type Observation struct {
+	ID    string          `json:"id"`
+	Round *shootout.Round `json:"round"`
+}
+
+// Step is an outcome of agent's action. Reward is damage agent dealt in the
+// round plus a point for surviving it.
+type Step struct {
+	Observation *Observation `json:"observation"`
+	Reward      float64      `json:"reward"`
+	Done        bool         `json:"done"`
+	Info        *Info        `json:"info"`
+}
+
+type Info struct {
+	// Rejected is a reason agent's action was rejected, round is lost then.
+	Rejected string           `json:"rejected,omitempty"`
+	Result   *shootout.Result `json:"result,omitempty"`
+}
+
+// Env is a step/reset environment around a single game. It is not safe for
+// concurrent use.
+type Env struct {
+	cfg       app.ArbiterConfig
+	agent     shootout.Competitor
+	opponents []shootout.Competitor
+	game      *simulation.Game
+	done      bool
+}
+
+func NewEnv(game *app.ArbiterConfig, cfg *Config) (*Env, error) {
+	if len(cfg.Opponents) == 0 {
+		return nil, ErrNoOpponents
+	}
+
+	env := &Env{
+		cfg:       *game,
+		agent:     cfg.Agent,
+		opponents: make([]shootout.Competitor, len(cfg.Opponents)),
+	}
+
+	env.agent.ID = agentID
+	for i := range cfg.Opponents {
+		env.opponents[i] = cfg.Opponents[i]
+		env.opponents[i].ID = fmt.Sprintf("opponent_%d", i+1)
+	}
+
+	// names have to be unique
+	env.agent.Name = agentID
+	for i := range env.opponents {
+		env.opponents[i].Name = env.opponents[i].ID
+	}
+
+	// competitors are validated up front, so every reset can start the game
+	if _, err := simulation.NewGame(&env.cfg, env.roster()); err != nil {
+		return nil, err
+	}
+
+	return env, nil
+}
+
+func (e *Env) roster() []shootout.Competitor {
+	return append([]shootout.Competitor{e.agent}, e.opponents...)
+}
+
+// Reset starts a new game, non-zero seed makes probabilistic combat repeatable.
+func (e *Env) Reset(seed int64) (*Observation, error) {
+	cfg := e.cfg
+	if seed != 0 {
+		cfg.Seed = seed
+	}
+
+	game, err := simulation.NewGame(&cfg, e.roster())
+	if err != nil {
+		return nil, err
+	}
+
+	e.game = game
+	e.done = false
+
+	return &Observation{ID: agentID, Round: game.Round()}, nil
+}
+
+// Step plays a round: agent acts first, opponents respond to the same round
+// and the round ends. Action is a shot when kind is omitted.
+func (e *Env) Step(action *shootout.Action) (*Step, error) {
+	if e.game == nil {
+		return nil, ErrNotReset
+	}
+
+	if e.done {
+		return nil, ErrEpisodeOver
+	}
+
+	step := &Step{Info: new(Info)}
+
+	agentAction := *action
+	agentAction.From = agentID
+	if agentAction.Kind == "" {
+		agentAction.Kind = shootout.ActionShoot
+	}
+
+	if err := e.game.Act(&agentAction); err != nil {
+		step.Info.Rejected = err.Error()
+	}
+
+	round := e.game.Round()
+	for _, opponent := range e.opponents {
+		if _, ok := round.Competitors[opponent.ID]; !ok {
+			continue
+		}
+
+		// builtin strategy picks targets in ID order, so seeded episodes repeat
+		opponentAction, err := strategy.Builtin(opponent.ID, opponent.Health, round)
+		if err != nil {
+			continue
+		}
+
+		// opponents get no second chance either
+		_ = e.game.Act(opponentAction)
+	}
+
+	if err := e.game.Next(); err != nil {
+		return nil, err
+	}
+
+	round = e.game.Round()
+
+	for _, hit := range round.Feed {
+		if hit.From == agentID {
+			step.Reward += float64(hit.Damage)
+		}
+	}
+
+	_, alive := round.Competitors[agentID]
+	if alive {
+		step.Reward += survivalReward
+	}
+
+	e.done = !alive || round.Result != nil
+	step.Done = e.done
+	step.Info.Result = round.Result
+	step.Observation = &Observation{ID: agentID, Round: round}
+
+	return step, nil
+}
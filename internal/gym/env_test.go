@@ -0,0 +1,128 @@
+package gym
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/damejeras/shootout/internal/app"
+	"github.com/damejeras/shootout/internal/shootout"
+)
+
+func TestEnv(t *testing.T) {
+	if _, err := NewEnv(&app.ArbiterConfig{}, &Config{}); err != ErrNoOpponents {
+		t.Fatalf("expected err %v, got %v", ErrNoOpponents, err)
+	}
+
+	var validationErr *shootout.ValidationError
+	if _, err := NewEnv(&app.ArbiterConfig{MaxHealth: 5}, &Config{
+		Agent:     shootout.Competitor{Health: 10, Damage: 3},
+		Opponents: []shootout.Competitor{{Health: 5, Damage: 1}},
+	}); !errors.As(err, &validationErr) {
+		t.Fatalf("expected validation err, got %v", err)
+	}
+
+	env, err := NewEnv(&app.ArbiterConfig{}, &Config{
+		Agent:     shootout.Competitor{Health: 10, Damage: 3},
+		Opponents: []shootout.Competitor{{Health: 5, Damage: 1}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	if _, err := env.Step(&shootout.Action{To: "opponent_1"}); err != ErrNotReset {
+		t.Fatalf("expected err %v, got %v", ErrNotReset, err)
+	}
+
+	observation, err := env.Reset(0)
+	if err != nil {
+		t.Fatalf("unexpected reset err: %v", err)
+	}
+
+	if observation.ID != agentID || len(observation.Round.Competitors) != 2 {
+		t.Fatalf("unexpected observation %+v", observation)
+	}
+
+	step, err := env.Step(&shootout.Action{To: "opponent_1"})
+	if err != nil {
+		t.Fatalf("unexpected step err: %v", err)
+	}
+
+	if step.Reward != 4 || step.Done {
+		t.Fatalf("expected reward 4 for damage and survival, got %+v", step)
+	}
+
+	if health := step.Observation.Round.Competitors[agentID].Health; health != 9 {
+		t.Fatalf("opponent should shoot back, expected health 9, got %d", health)
+	}
+
+	step, err = env.Step(&shootout.Action{Kind: shootout.ActionMove, Position: &shootout.Position{X: 1}})
+	if err != nil {
+		t.Fatalf("unexpected step err: %v", err)
+	}
+
+	if step.Info.Rejected == "" || step.Reward != 1 {
+		t.Fatalf("move outside of arena should be rejected, got %+v", step)
+	}
+
+	step, err = env.Step(&shootout.Action{To: "opponent_1"})
+	if err != nil {
+		t.Fatalf("unexpected step err: %v", err)
+	}
+
+	if !step.Done || step.Info.Result == nil || step.Info.Result.Winner != agentID {
+		t.Fatalf("agent should win, got %+v", step.Info)
+	}
+
+	if _, err := env.Step(&shootout.Action{To: "opponent_1"}); err != ErrEpisodeOver {
+		t.Fatalf("expected err %v, got %v", ErrEpisodeOver, err)
+	}
+
+	if _, err := env.Reset(0); err != nil {
+		t.Fatalf("unexpected reset err: %v", err)
+	}
+}
+
+func TestEnvReplay(t *testing.T) {
+	play := func() []float64 {
+		env, err := NewEnv(&app.ArbiterConfig{ProbabilisticCombat: true}, &Config{
+			Agent:     shootout.Competitor{Health: 10, Damage: 2},
+			Opponents: []shootout.Competitor{{Health: 10, Damage: 1}, {Health: 10, Damage: 1}, {Health: 10, Damage: 1}},
+		})
+		if err != nil {
+			t.Fatalf("unexpected err: %v", err)
+		}
+
+		if _, err := env.Reset(7); err != nil {
+			t.Fatalf("unexpected reset err: %v", err)
+		}
+
+		var health []float64
+		for i := 0; i < 20; i++ {
+			step, err := env.Step(&shootout.Action{To: "opponent_1"})
+			if err != nil {
+				t.Fatalf("unexpected step err: %v", err)
+			}
+
+			for _, id := range []string{agentID, "opponent_1", "opponent_2", "opponent_3"} {
+				if competitor, ok := step.Observation.Round.Competitors[id]; ok {
+					health = append(health, float64(competitor.Health))
+				}
+			}
+
+			if step.Done {
+				break
+			}
+		}
+
+		return health
+	}
+
+	// opponents pick targets the same way every time, so seeded episodes repeat
+	first := play()
+	for i := 0; i < 10; i++ {
+		if again := play(); fmt.Sprint(again) != fmt.Sprint(first) {
+			t.Fatalf("expected episode %v to repeat, got %v", first, again)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package gym
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/damejeras/shootout/internal/app"
+	"github.com/damejeras/shootout/internal/infrastructure"
+	"github.com/damejeras/shootout/internal/shootout"
+	"github.com/google/uuid"
+)
+
+const (
+	// maxEnvs bounds memory held by the server, environments idle for
+	// envIdleTimeout are closed to make room for new ones.
+	maxEnvs        = 100
+	envIdleTimeout = 30 * time.Minute
+)
+
+type createRequest struct {
+	Config
+}
+
+type createResponse struct {
+	InstanceID string `json:"instance_id"`
+}
+
+type resetRequest struct {
+	Seed int64 `json:"seed,omitempty"`
+}
+
+type resetResponse struct {
+	Observation *Observation `json:"observation"`
+}
+
+type stepRequest struct {
+	Action *shootout.Action `json:"action"`
+}
+
+// Server exposes environments over HTTP in the manner of Gym HTTP API, so
+// policies can be trained by clients in any language. Every instance is a
+// separate environment.
+type Server struct {
+	cfg         *app.ArbiterConfig
+	logger      *log.Logger
+	envs        map[string]*instance
+	maxEnvs     int
+	idleTimeout time.Duration
+	lock        sync.Mutex
+}
+
+// instance serializes access to its environment.
+type instance struct {
+	env  *Env
+	lock sync.Mutex
+	// used is guarded by server's lock
+	used time.Time
+}
+
+func NewServer(cfg *app.ArbiterConfig, logger *log.Logger) *Server {
+	return &Server{
+		cfg:         cfg,
+		logger:      logger,
+		envs:        make(map[string]*instance),
+		maxEnvs:     maxEnvs,
+		idleTimeout: envIdleTimeout,
+	}
+}
+
+func (s *Server) Run() {
+	if err := infrastructure.HTTPServer(s.cfg.Port, s.Routes()).ListenAndServe(); err != nil {
+		s.logger.Printf("gym HTTP server listen: %v", err)
+	}
+}
+
+func (s *Server) Routes() http.Handler {
+	router := infrastructure.NewRouter()
+	router.Handle(http.MethodPost, "/v1/envs", s.handleCreate)
+	router.Handle(http.MethodPost, "/v1/envs/{id}/reset", s.handleReset)
+	router.Handle(http.MethodPost, "/v1/envs/{id}/step", s.handleStep)
+	router.Handle(http.MethodDelete, "/v1/envs/{id}", s.handleClose)
+
+	return router
+}
+
+func (s *Server) handleCreate(w http.ResponseWriter, r *http.Request) {
+	var request createRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		infrastructure.WriteError(w, http.StatusBadRequest, infrastructure.ErrorCodeBadRequest, "malformed request body")
+		return
+	}
+
+	env, err := NewEnv(s.cfg, &request.Config)
+	if err != nil {
+		s.writeEnvError(w, err)
+		return
+	}
+
+	id := uuid.NewString()
+
+	s.lock.Lock()
+	s.expire()
+	if len(s.envs) >= s.maxEnvs {
+		s.lock.Unlock()
+		s.writeEnvError(w, ErrTooManyEnvs)
+		return
+	}
+
+	s.envs[id] = &instance{env: env, used: time.Now()}
+	s.lock.Unlock()
+
+	s.respond(w, http.StatusCreated, &createResponse{InstanceID: id})
+}
+
+func (s *Server) handleReset(w http.ResponseWriter, r *http.Request) {
+	var request resetRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			infrastructure.WriteError(w, http.StatusBadRequest, infrastructure.ErrorCodeBadRequest, "malformed request body")
+			return
+		}
+	}
+
+	inst, ok := s.instance(infrastructure.PathParam(r, "id"))
+	if !ok {
+		infrastructure.WriteError(w, http.StatusNotFound, infrastructure.ErrorCodeNotFound, "environment not found")
+		return
+	}
+
+	inst.lock.Lock()
+	defer inst.lock.Unlock()
+
+	observation, err := inst.env.Reset(request.Seed)
+	if err != nil {
+		s.writeEnvError(w, err)
+		return
+	}
+
+	s.respond(w, http.StatusOK, &resetResponse{Observation: observation})
+}
+
+func (s *Server) handleStep(w http.ResponseWriter, r *http.Request) {
+	var request stepRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Action == nil {
+		infrastructure.WriteError(w, http.StatusBadRequest, infrastructure.ErrorCodeBadRequest, "malformed request body")
+		return
+	}
+
+	inst, ok := s.instance(infrastructure.PathParam(r, "id"))
+	if !ok {
+		infrastructure.WriteError(w, http.StatusNotFound, infrastructure.ErrorCodeNotFound, "environment not found")
+		return
+	}
+
+	inst.lock.Lock()
+	defer inst.lock.Unlock()
+
+	step, err := inst.env.Step(request.Action)
+	if err != nil {
+		s.writeEnvError(w, err)
+		return
+	}
+
+	s.respond(w, http.StatusOK, step)
+}
+
+func (s *Server) handleClose(w http.ResponseWriter, r *http.Request) {
+	id := infrastructure.PathParam(r, "id")
+
+	s.lock.Lock()
+	_, ok := s.envs[id]
+	delete(s.envs, id)
+	s.lock.Unlock()
+
+	if !ok {
+		infrastructure.WriteError(w, http.StatusNotFound, infrastructure.ErrorCodeNotFound, "environment not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) instance(id string) (*instance, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	inst, ok := s.envs[id]
+	if ok {
+		inst.used = time.Now()
+	}
+
+	return inst, ok
+}
+
+// expire closes environments clients abandoned, server's lock must be held.
+func (s *Server) expire() {
+	for id, inst := range s.envs {
+		if time.Since(inst.used) > s.idleTimeout {
+			s.logger.Printf("environment %s expired", id)
+			delete(s.envs, id)
+		}
+	}
+}
+
+func (s *Server) respond(w http.ResponseWriter, status int, body interface{}) {
+	if err := infrastructure.WriteJSON(w, status, body); err != nil {
+		s.logger.Printf("encode response: %v", err)
+	}
+}
+
+func (s *Server) writeEnvError(w http.ResponseWriter, err error) {
+	var validationErr *shootout.ValidationError
+	if errors.As(err, &validationErr) {
+		infrastructure.WriteErrorDetails(
+			w,
+			http.StatusBadRequest,
+			infrastructure.ErrorCodeValidation,
+			err.Error(),
+			validationErr.Violations,
+		)
+		return
+	}
+
+	switch {
+	case errors.Is(err, ErrNoOpponents),
+		errors.Is(err, shootout.ErrInvalidRegistration),
+		errors.Is(err, shootout.ErrArenaFull):
+		infrastructure.WriteError(w, http.StatusBadRequest, infrastructure.ErrorCodeBadRequest, err.Error())
+	case errors.Is(err, ErrNotReset), errors.Is(err, ErrEpisodeOver), errors.Is(err, ErrTooManyEnvs):
+		infrastructure.WriteError(w, http.StatusConflict, infrastructure.ErrorCodeConflict, err.Error())
+	default:
+		s.logger.Printf("environment: %v", err)
+		infrastructure.WriteError(w, http.StatusInternalServerError, infrastructure.ErrorCodeInternal, err.Error())
+	}
+}
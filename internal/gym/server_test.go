@@ -0,0 +1,56 @@
+package gym
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/damejeras/shootout/internal/app"
+)
+
+func TestServerEnvLimits(t *testing.T) {
+	server := NewServer(&app.ArbiterConfig{}, log.New(io.Discard, "", 0))
+	server.maxEnvs = 2
+	routes := server.Routes()
+
+	create := func() int {
+		body := `{"agent": {"health": 10, "damage": 2}, "opponents": [{"health": 10, "damage": 1}]}`
+		recorder := httptest.NewRecorder()
+		routes.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/v1/envs", strings.NewReader(body)))
+
+		return recorder.Code
+	}
+
+	for i := 0; i < 2; i++ {
+		if code := create(); code != http.StatusCreated {
+			t.Fatalf("expected status %d, got %d", http.StatusCreated, code)
+		}
+	}
+
+	if code := create(); code != http.StatusConflict {
+		t.Fatalf("expected status %d over the limit, got %d", http.StatusConflict, code)
+	}
+
+	var abandoned string
+	for id := range server.envs {
+		abandoned = id
+	}
+
+	// idle environments make room for new ones
+	server.idleTimeout = time.Nanosecond
+	time.Sleep(time.Millisecond)
+
+	if code := create(); code != http.StatusCreated {
+		t.Fatalf("expected status %d after expiry, got %d", http.StatusCreated, code)
+	}
+
+	recorder := httptest.NewRecorder()
+	routes.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/v1/envs/"+abandoned+"/reset", nil))
+	if recorder.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d for expired environment, got %d", http.StatusNotFound, recorder.Code)
+	}
+}
@@ -10,6 +10,15 @@ const (
 	EventRegistration           = "registration"
 	EventRound                  = "round"
 	EventShot                   = "shot"
+	EventAction                 = "action"
+	EventHit                    = "hit"
+	EventKill                   = "kill"
+	EventMiss                   = "miss"
+	// directed to a single competitor
+	EventAck        = "ack"
+	EventRejection  = "rejection"
+	EventRegistered = "registered"
+	EventStats      = "stats"
 )
 
 type EventType string
@@ -1,13 +1,56 @@
 package infrastructure
 
-import "net/http"
+import (
+	"encoding/json"
+	"net/http"
+)
 
-func SingleEndpointHTTPServer(port, path string, handlerFunc http.HandlerFunc) *http.Server {
-	mux := http.NewServeMux()
-	mux.HandleFunc(path, handlerFunc)
+const (
+	ErrorCodeBadRequest       = "bad_request"
+	ErrorCodeValidation       = "validation_failed"
+	ErrorCodeNotFound         = "not_found"
+	ErrorCodeMethodNotAllowed = "method_not_allowed"
+	ErrorCodeForbidden        = "forbidden"
+	ErrorCodeConflict         = "conflict"
+	ErrorCodeInternal         = "internal_error"
+)
 
+type ErrorResponse struct {
+	Error ErrorBody `json:"error"`
+}
+
+type ErrorBody struct {
+	Code    string      `json:"code"`
+	Message string      `json:"message"`
+	Details interface{} `json:"details,omitempty"`
+}
+
+func HTTPServer(port string, handler http.Handler) *http.Server {
 	return &http.Server{
 		Addr:    port,
-		Handler: mux,
+		Handler: handler,
 	}
 }
+
+func WriteJSON(w http.ResponseWriter, status int, body interface{}) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	return json.NewEncoder(w).Encode(body)
+}
+
+// WriteError responds with JSON error body. Encoding error is ignored, because
+// there is no other way left to report it to the client.
+func WriteError(w http.ResponseWriter, status int, code, message string) {
+	WriteErrorDetails(w, status, code, message, nil)
+}
+
+func WriteErrorDetails(w http.ResponseWriter, status int, code, message string, details interface{}) {
+	_ = WriteJSON(w, status, &ErrorResponse{
+		Error: ErrorBody{
+			Code:    code,
+			Message: message,
+			Details: details,
+		},
+	})
+}
@@ -0,0 +1,46 @@
+package infrastructure
+
+import "sync"
+
+const subscriberBuffer = 64
+
+// Hub fans out events to any number of subscribers. Slow subscribers miss
+// events instead of blocking the publisher.
+type Hub struct {
+	lock        *sync.Mutex
+	subscribers map[chan *Event]struct{}
+}
+
+func NewHub() *Hub {
+	return &Hub{
+		lock:        new(sync.Mutex),
+		subscribers: make(map[chan *Event]struct{}),
+	}
+}
+
+func (h *Hub) Publish(event *Event) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	for subscriber := range h.subscribers {
+		select {
+		case subscriber <- event:
+		default:
+		}
+	}
+}
+
+func (h *Hub) Subscribe() (<-chan *Event, func()) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	subscriber := make(chan *Event, subscriberBuffer)
+	h.subscribers[subscriber] = struct{}{}
+
+	return subscriber, func() {
+		h.lock.Lock()
+		defer h.lock.Unlock()
+
+		delete(h.subscribers, subscriber)
+	}
+}
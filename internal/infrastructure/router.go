@@ -0,0 +1,107 @@
+package infrastructure
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+type pathParamsKey struct{}
+
+type route struct {
+	method   string
+	segments []string
+	handler  http.HandlerFunc
+}
+
+// Router matches requests by method and path. Pattern segments wrapped in
+// braces capture path parameters, trailing "*" segment matches any suffix.
+// Path matched by wildcard pattern only is not found for other methods, so
+// catch-all routes do not turn every unknown path into method not allowed.
+type Router struct {
+	routes []*route
+}
+
+func NewRouter() *Router {
+	return new(Router)
+}
+
+func (r *Router) Handle(method, pattern string, handler http.HandlerFunc) {
+	r.routes = append(r.routes, &route{
+		method:   method,
+		segments: split(pattern),
+		handler:  handler,
+	})
+}
+
+func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	segments := split(req.URL.Path)
+	pathMatched := false
+
+	for _, candidate := range r.routes {
+		params, ok := candidate.match(segments)
+		if !ok {
+			continue
+		}
+
+		if candidate.method != req.Method {
+			pathMatched = pathMatched || !candidate.wildcard()
+			continue
+		}
+
+		candidate.handler(w, req.WithContext(context.WithValue(req.Context(), pathParamsKey{}, params)))
+
+		return
+	}
+
+	if pathMatched {
+		WriteError(w, http.StatusMethodNotAllowed, ErrorCodeMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	WriteError(w, http.StatusNotFound, ErrorCodeNotFound, "not found")
+}
+
+func PathParam(r *http.Request, name string) string {
+	params, _ := r.Context().Value(pathParamsKey{}).(map[string]string)
+
+	return params[name]
+}
+
+func (r *route) wildcard() bool {
+	return len(r.segments) > 0 && r.segments[len(r.segments)-1] == "*"
+}
+
+func (r *route) match(segments []string) (map[string]string, bool) {
+	params := make(map[string]string)
+
+	for i, segment := range r.segments {
+		if segment == "*" {
+			return params, true
+		}
+
+		if i >= len(segments) {
+			return nil, false
+		}
+
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			params[strings.Trim(segment, "{}")] = segments[i]
+			continue
+		}
+
+		if segment != segments[i] {
+			return nil, false
+		}
+	}
+
+	return params, len(segments) == len(r.segments)
+}
+
+func split(path string) []string {
+	trimmed := strings.Trim(path, "/")
+	if trimmed == "" {
+		return nil
+	}
+
+	return strings.Split(trimmed, "/")
+}
@@ -0,0 +1,57 @@
+package infrastructure
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRouter(t *testing.T) {
+	router := NewRouter()
+	router.Handle(http.MethodGet, "/competitors/{id}", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("get " + PathParam(r, "id")))
+	})
+	router.Handle(http.MethodDelete, "/competitors/{id}", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("delete " + PathParam(r, "id")))
+	})
+	router.Handle(http.MethodGet, "/assets/*", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("fallback"))
+	})
+	router.Handle(http.MethodGet, "/*", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("dashboard"))
+	})
+
+	for _, tc := range []struct {
+		method, path string
+		status       int
+		body         string
+	}{
+		{http.MethodGet, "/competitors/abc", http.StatusOK, "get abc"},
+		{http.MethodDelete, "/competitors/abc/", http.StatusOK, "delete abc"},
+		{http.MethodGet, "/assets", http.StatusOK, "fallback"},
+		{http.MethodGet, "/assets/app.js", http.StatusOK, "fallback"},
+		{http.MethodPost, "/competitors/abc", http.StatusMethodNotAllowed, ""},
+		{http.MethodGet, "/unknown", http.StatusOK, "dashboard"},
+		{http.MethodPost, "/unknown", http.StatusNotFound, ""},
+		{http.MethodPost, "/assets/app.js", http.StatusNotFound, ""},
+	} {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(tc.method, tc.path, nil))
+
+		if recorder.Code != tc.status {
+			t.Fatalf("%s %s: expected status %d, got %d", tc.method, tc.path, tc.status, recorder.Code)
+		}
+
+		if tc.body != "" && recorder.Body.String() != tc.body {
+			t.Fatalf("%s %s: expected body %q, got %q", tc.method, tc.path, tc.body, recorder.Body.String())
+		}
+
+		if tc.body == "" {
+			var response ErrorResponse
+			if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil || response.Error.Code == "" {
+				t.Fatalf("%s %s: expected JSON error body, got err %v", tc.method, tc.path, err)
+			}
+		}
+	}
+}
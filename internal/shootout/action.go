@@ -0,0 +1,192 @@
+package shootout
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/damejeras/shootout/internal/app"
+	"github.com/damejeras/shootout/internal/infrastructure"
+)
+
+const (
+	ActionShoot ActionKind = "shoot"
+	ActionHeal  ActionKind = "heal"
+	ActionCover ActionKind = "cover"
+	ActionAim   ActionKind = "aim"
+	ActionMove  ActionKind = "move"
+	ActionUse   ActionKind = "use"
+)
+
+const (
+	defaultHealAmount = 2
+	defaultAimBonus   = 50
+)
+
+var ErrAlreadyActed = fmt.Errorf("competitor already acted this round")
+
+type ActionKind string
+
+// Action competitor takes in a round, competitor can act once per round.
+type Action struct {
+	Kind ActionKind `json:"kind"`
+	From string     `json:"from"`
+	// To is a target of the shot.
+	To string `json:"to,omitempty"`
+	// Position competitor moves to.
+	Position *Position `json:"position,omitempty"`
+	// Item competitor uses.
+	Item string `json:"item,omitempty"`
+}
+
+// actionRule applies action of the competitor to the state.
+type actionRule func(s *State, competitor *Competitor, action *Action) error
+
+var actionRules = map[ActionKind]actionRule{
+	ActionShoot: (*State).shoot,
+	ActionHeal:  (*State).heal,
+	ActionCover: (*State).cover,
+	ActionAim:   (*State).aim,
+	ActionMove:  (*State).move,
+	ActionUse:   (*State).use,
+}
+
+// tactics configures actions other than shooting.
+type tactics struct {
+	healAmount int
+	aimBonus   int
+}
+
+func newTactics(cfg *app.ArbiterConfig) *tactics {
+	t := &tactics{
+		healAmount: cfg.HealAmount,
+		aimBonus:   cfg.AimBonus,
+	}
+
+	if t.healAmount == 0 {
+		t.healAmount = defaultHealAmount
+	}
+
+	if t.aimBonus == 0 {
+		t.aimBonus = defaultAimBonus
+	}
+
+	return t
+}
+
+func (s *State) handleAction(event *infrastructure.Event) error {
+	var action Action
+	if err := json.Unmarshal(event.Data, &action); err != nil {
+		return fmt.Errorf("unmarshal action payload: %w", err)
+	}
+
+	return s.act(&action)
+}
+
+func (s *State) act(action *Action) error {
+	switch s.phase {
+	case PhaseRegistration:
+		return ErrNotStarted
+	case PhasePaused:
+		return ErrPaused
+	case PhaseFinished:
+		return ErrFinished
+	}
+
+	if action.From == "" {
+		return ErrUnacceptablePayload
+	}
+
+	competitor, ok := s.competitors[action.From]
+	if !ok {
+		// too late, sorry
+		return nil
+	}
+
+	if s.acted[action.From] {
+		return ErrAlreadyActed
+	}
+
+	if err := s.rules.Apply(s, competitor, action); err != nil {
+		// in turn-based mode rejected action takes competitor's turn all the
+		// same, round does not wait for a competitor who keeps acting wrong
+		s.acted[action.From] = s.turnBased
+
+		return err
+	}
+
+	s.acted[action.From] = true
+	s.idle = 0
+
+	return nil
+}
+
+// Ready reports whether every competitor who can act already acted this round.
+func (s *State) Ready() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.phase != PhaseRunning {
+		return false
+	}
+
+	for id, competitor := range s.competitors {
+		if !s.acted[id] && !affected(competitor, EffectStunned) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// heal restores competitor's health, but not above health competitor registered with.
+func (s *State) heal(competitor *Competitor, _ *Action) error {
+	competitor.Health += s.tactics.healAmount
+	if competitor.Health > s.vitality[competitor.ID] {
+		competitor.Health = s.vitality[competitor.ID]
+	}
+
+	log.Printf("🩹 %s healed up to %d 🩹", competitor.Name, competitor.Health)
+
+	return nil
+}
+
+// cover halves damage competitor takes during the next round.
+func (s *State) cover(competitor *Competitor, _ *Action) error {
+	s.covering[competitor.ID] = true
+
+	log.Printf("🪨 %s takes cover 🪨", competitor.Name)
+
+	return nil
+}
+
+// aim boosts damage of competitor's next shot.
+func (s *State) aim(competitor *Competitor, _ *Action) error {
+	competitor.Aiming = true
+
+	log.Printf("🎯 %s takes aim 🎯", competitor.Name)
+
+	return nil
+}
+
+// takeCover moves competitors who took cover last round behind it for the
+// current round.
+func (s *State) takeCover() {
+	for id, competitor := range s.competitors {
+		competitor.InCover = s.covering[id]
+	}
+
+	s.covering = make(map[string]bool)
+}
+
+func halve(damage int) int {
+	if damage == 0 {
+		return 0
+	}
+
+	if damage/2 < minimumDamage {
+		return minimumDamage
+	}
+
+	return damage / 2
+}
@@ -0,0 +1,242 @@
+package shootout
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/damejeras/shootout/internal/app"
+)
+
+const defaultMove = 1
+
+var (
+	ErrArenaFull     = fmt.Errorf("no free cell left in the arena")
+	ErrInvalidMove   = fmt.Errorf("competitor can not move there")
+	ErrOutOfRange    = fmt.Errorf("target is out of range")
+	ErrNoLineOfSight = fmt.Errorf("target is not visible")
+)
+
+type Position = app.Position
+
+// Arena is a grid competitors fight in, it is sent with every round so
+// competitors can plan their moves. Spawns are kept to the arbiter.
+type Arena struct {
+	app.Arena
+
+	spawns []Position
+}
+
+func newArena(cfg *app.ArbiterConfig) *Arena {
+	if cfg.Battlefield == nil {
+		return nil
+	}
+
+	arena := &Arena{
+		Arena:  *cfg.Battlefield,
+		spawns: cfg.Battlefield.Spawns,
+	}
+
+	arena.Spawns = nil
+
+	if arena.Move == 0 {
+		arena.Move = defaultMove
+	}
+
+	return arena
+}
+
+// Free reports whether competitor can stand at position.
+func (a *Arena) Free(position Position) bool {
+	if position.X < 0 || position.Y < 0 || position.X >= a.Width || position.Y >= a.Height {
+		return false
+	}
+
+	for _, obstacle := range a.Obstacles {
+		if obstacle == position {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (a *Arena) Distance(from, to Position) float64 {
+	return math.Hypot(float64(to.X-from.X), float64(to.Y-from.Y))
+}
+
+func (a *Arena) InRange(from, to Position) bool {
+	return a.Range == 0 || a.Distance(from, to) <= float64(a.Range)
+}
+
+// Visible reports whether there are no obstacles on the line between positions.
+func (a *Arena) Visible(from, to Position) bool {
+	dx, dy := abs(to.X-from.X), -abs(to.Y-from.Y)
+	sx, sy := sign(to.X-from.X), sign(to.Y-from.Y)
+	err := dx + dy
+
+	// Bresenham's line, cells of competitors themselves are not checked
+	for x, y := from.X, from.Y; x != to.X || y != to.Y; {
+		if (x != from.X || y != from.Y) && !a.Free(Position{X: x, Y: y}) {
+			return false
+		}
+
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x += sx
+		}
+
+		if e2 <= dx {
+			err += dx
+			y += sy
+		}
+	}
+
+	return true
+}
+
+// Step returns the free cell next to position that is the closest to the
+// destination, cells in taken are treated as occupied.
+func (a *Arena) Step(from, to Position, taken []Position) (Position, bool) {
+	best, found := from, false
+	for dx := -1; dx <= 1; dx++ {
+		for dy := -1; dy <= 1; dy++ {
+			next := Position{X: from.X + dx, Y: from.Y + dy}
+			if next == from || !a.Free(next) || containsPosition(taken, next) {
+				continue
+			}
+
+			if a.Distance(next, to) < a.Distance(best, to) {
+				best, found = next, true
+			}
+		}
+	}
+
+	return best, found
+}
+
+// falloff lowers damage with distance, but hit always inflicts some damage.
+func (a *Arena) falloff(damage int, distance float64) int {
+	if damage == 0 || a.Falloff == 0 || distance <= 1 {
+		return damage
+	}
+
+	damage = int(float64(damage) * (1 - float64(a.Falloff)*(distance-1)/100))
+	if damage < minimumDamage {
+		return minimumDamage
+	}
+
+	return damage
+}
+
+// spawn places competitor at the next spawn or at random free cell.
+func (s *State) spawn(competitor *Competitor) error {
+	if s.arena == nil {
+		return nil
+	}
+
+	taken := s.positions()
+	for len(s.arena.spawns) > 0 {
+		spawn := s.arena.spawns[0]
+		s.arena.spawns = s.arena.spawns[1:]
+
+		if s.arena.Free(spawn) && !containsPosition(taken, spawn) {
+			competitor.Position = &spawn
+			return nil
+		}
+	}
+
+	var free []Position
+	for x := 0; x < s.arena.Width; x++ {
+		for y := 0; y < s.arena.Height; y++ {
+			position := Position{X: x, Y: y}
+			if s.arena.Free(position) && !containsPosition(taken, position) {
+				free = append(free, position)
+			}
+		}
+	}
+
+	if len(free) == 0 {
+		return ErrArenaFull
+	}
+
+	position := free[s.combat.rng.Intn(len(free))]
+	competitor.Position = &position
+
+	return nil
+}
+
+// move competitor up to arena's move distance, competitor can jump over obstacles.
+func (s *State) move(competitor *Competitor, action *Action) error {
+	if s.arena == nil || action.Position == nil {
+		return ErrUnacceptablePayload
+	}
+
+	destination := *action.Position
+	dx, dy := abs(destination.X-competitor.Position.X), abs(destination.Y-competitor.Position.Y)
+	if dx > s.arena.Move || dy > s.arena.Move || !s.arena.Free(destination) ||
+		containsPosition(s.positions(), destination) {
+		return ErrInvalidMove
+	}
+
+	competitor.Position = &destination
+
+	return nil
+}
+
+// aimAt checks whether shooter can shoot target in the arena.
+func (s *State) aimAt(shooter, target *Competitor) error {
+	if s.arena == nil {
+		return nil
+	}
+
+	if !s.arena.InRange(*shooter.Position, *target.Position) {
+		return ErrOutOfRange
+	}
+
+	if !s.arena.Visible(*shooter.Position, *target.Position) {
+		return ErrNoLineOfSight
+	}
+
+	return nil
+}
+
+func (s *State) positions() []Position {
+	var positions []Position
+	for _, competitor := range s.competitors {
+		if competitor.Position != nil {
+			positions = append(positions, *competitor.Position)
+		}
+	}
+
+	return positions
+}
+
+func containsPosition(positions []Position, position Position) bool {
+	for i := range positions {
+		if positions[i] == position {
+			return true
+		}
+	}
+
+	return false
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+
+	return x
+}
+
+func sign(x int) int {
+	switch {
+	case x < 0:
+		return -1
+	case x > 0:
+		return 1
+	default:
+		return 0
+	}
+}
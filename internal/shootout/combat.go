@@ -0,0 +1,80 @@
+package shootout
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/damejeras/shootout/internal/app"
+)
+
+const (
+	defaultBaseHitChance = 75
+	defaultCritDamage    = 200
+	// armor can not make competitor invulnerable
+	minimumDamage = 1
+)
+
+// combat resolves shots. Deterministic combat always hits for shooter's
+// damage. Probabilistic combat rolls for a hit, where shooter's accuracy
+// improves and target's speed lowers the chance, and for a critical hit.
+type combat struct {
+	probabilistic bool
+	seed          int64
+	baseHitChance int
+	critChance    int
+	critDamage    int
+	rng           *rand.Rand
+}
+
+func newCombat(cfg *app.ArbiterConfig) *combat {
+	c := &combat{
+		probabilistic: cfg.ProbabilisticCombat,
+		seed:          cfg.Seed,
+		baseHitChance: defaultBaseHitChance,
+		critChance:    cfg.CritChance,
+		critDamage:    cfg.CritDamage,
+	}
+
+	if c.seed == 0 {
+		c.seed = time.Now().UnixNano()
+	}
+
+	if cfg.BaseHitChance != nil {
+		c.baseHitChance = *cfg.BaseHitChance
+	}
+
+	if c.critDamage == 0 {
+		c.critDamage = defaultCritDamage
+	}
+
+	c.rng = rand.New(rand.NewSource(c.seed))
+
+	return c
+}
+
+// resolve shot, bonus is added to shooter's damage.
+func (c *combat) resolve(shooter, target *Competitor, bonus int) (Outcome, int) {
+	damage := shooter.Damage + bonus
+
+	if !c.probabilistic {
+		return OutcomeHit, mitigate(damage, target.Armor)
+	}
+
+	if c.rng.Intn(100) >= c.baseHitChance+shooter.Accuracy-target.Speed {
+		return OutcomeMiss, 0
+	}
+
+	if c.rng.Intn(100) < c.critChance {
+		return OutcomeCritical, mitigate(damage*c.critDamage/100, target.Armor)
+	}
+
+	return OutcomeHit, mitigate(damage, target.Armor)
+}
+
+func mitigate(damage, armor int) int {
+	if damage-armor < minimumDamage {
+		return minimumDamage
+	}
+
+	return damage - armor
+}
@@ -0,0 +1,62 @@
+package shootout
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/damejeras/shootout/internal/app"
+)
+
+// DecodeConfig decodes JSON catalogs and settings of the configuration loaded from
+// environment and validates rules the game is played by.
+func DecodeConfig(cfg *app.ArbiterConfig) error {
+	if _, err := Ruleset(cfg.Rules); err != nil {
+		return err
+	}
+
+	if cfg.RulesScript != "" {
+		if _, err := NewScriptRules(cfg, StandardRules{}); err != nil {
+			return err
+		}
+	}
+
+	switch SuddenDeath(cfg.SuddenDeath) {
+	case "", SuddenDeathEscalation, SuddenDeathShrink, SuddenDeathHealth:
+	default:
+		return fmt.Errorf("unknown sudden death rule %q", cfg.SuddenDeath)
+	}
+
+	if cfg.WeaponCatalog != "" {
+		if err := json.Unmarshal([]byte(cfg.WeaponCatalog), &cfg.Armory); err != nil {
+			return fmt.Errorf("decode weapon catalog: %w", err)
+		}
+	}
+
+	if cfg.ItemCatalog != "" {
+		if err := json.Unmarshal([]byte(cfg.ItemCatalog), &cfg.ItemEffects); err != nil {
+			return fmt.Errorf("decode item catalog: %w", err)
+		}
+	}
+
+	if cfg.BaseHitChanceSetting != "" {
+		chance, err := strconv.Atoi(cfg.BaseHitChanceSetting)
+		if err != nil {
+			return fmt.Errorf("decode base hit chance: %w", err)
+		}
+
+		if chance < 0 || chance > 100 {
+			return fmt.Errorf("base hit chance %d is not a percentage", chance)
+		}
+
+		cfg.BaseHitChance = &chance
+	}
+
+	if cfg.ArenaLayout != "" {
+		if err := json.Unmarshal([]byte(cfg.ArenaLayout), &cfg.Battlefield); err != nil {
+			return fmt.Errorf("decode arena: %w", err)
+		}
+	}
+
+	return nil
+}
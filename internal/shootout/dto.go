@@ -1,21 +1,151 @@
 package shootout
 
+import "reflect"
+
+const (
+	PhaseRegistration Phase = "registration"
+	PhaseRunning      Phase = "running"
+	PhasePaused       Phase = "paused"
+	PhaseFinished     Phase = "finished"
+)
+
+type Phase string
+
+const (
+	DecisionLastStanding        Decision = "last_standing"
+	DecisionAborted             Decision = "aborted"
+	DecisionInsufficientPlayers Decision = "insufficient_players"
+	// DecisionSuddenDeath is made when round limit is reached, result states
+	// which sudden death rule decided the game.
+	DecisionSuddenDeath Decision = "sudden_death"
+	// DecisionScripted is made by rules script.
+	DecisionScripted Decision = "scripted"
+)
+
+type Decision string
+
+const (
+	OutcomeHit      Outcome = "hit"
+	OutcomeMiss     Outcome = "miss"
+	OutcomeCritical Outcome = "critical"
+	// OutcomeBleeding is damage of bleeding, it comes from whoever caused it.
+	OutcomeBleeding Outcome = "bleeding"
+	// OutcomePenalty is damage shooter takes for shooting a teammate.
+	OutcomePenalty Outcome = "penalty"
+	// OutcomeSuddenDeath is damage of shrinking health in sudden death.
+	OutcomeSuddenDeath Outcome = "sudden_death"
+	// OutcomeScripted is damage rules script inflicted between rounds.
+	OutcomeScripted Outcome = "scripted"
+)
+
+type Outcome string
+
 type Competitor struct {
 	ID     string `json:"id"`
 	Name   string `json:"name"`
-	Health int    `json:"health"`
-	Damage int    `json:"damage"`
+	Health int    `json:"health,omitempty"`
+	Damage int    `json:"damage,omitempty"`
+	// Accuracy is a hit chance bonus in percent, used by probabilistic combat.
+	Accuracy int `json:"accuracy,omitempty"`
+	// Armor reduces damage of every incoming shot, down to the minimum of 1.
+	Armor int `json:"armor,omitempty"`
+	// Speed makes competitor harder to hit in probabilistic combat.
+	Speed int `json:"speed,omitempty"`
+	// Team competitor fights for, competitor without team fights alone.
+	Team string `json:"team,omitempty"`
+	// Weapon from arbiter's catalog, competitor shoots without limits when
+	// weapons are not used.
+	Weapon string `json:"weapon,omitempty"`
+	// Magazine holds rounds ready to be shot.
+	Magazine int `json:"magazine,omitempty"`
+	// Ammo carried besides loaded magazine.
+	Ammo int `json:"ammo,omitempty"`
+	// Reloading is a number of rounds left until magazine is loaded.
+	Reloading int `json:"reloading,omitempty"`
+	// InCover halves damage competitor takes this round.
+	InCover bool `json:"inCover,omitempty"`
+	// Aiming boosts damage of competitor's next shot.
+	Aiming bool `json:"aiming,omitempty"`
+	// Position in the arena, when game is played in one.
+	Position *Position `json:"position,omitempty"`
+	// Items competitor can use, every item can be used once.
+	Items []string `json:"items,omitempty"`
+	// Effects persisting across rounds.
+	Effects []*Effect `json:"effects,omitempty"`
+	// Condition stands for health of enemies under fog of war, health and
+	// stats of such enemies are omitted.
+	Condition Condition `json:"condition,omitempty"`
+
+	// reloadStarted during the last round and is not ticked yet
+	reloadStarted bool
 }
 
 func (c *Competitor) IsZero() bool {
-	return Competitor{} == *c
+	return reflect.ValueOf(*c).IsZero()
+}
+
+// Registration identifies competitor by key, competitor registered without
+// key can not rejoin the game. Key is not shared with other competitors.
+type Registration struct {
+	Competitor
+	Key string `json:"key,omitempty"`
 }
 
 type Round struct {
 	Competitors map[string]*Competitor
+	Feed        []*Hit  `json:"feed,omitempty"`
+	Result      *Result `json:"result,omitempty"`
+	Arena       *Arena  `json:"arena,omitempty"`
+}
+
+type Heartbeat struct {
+	Paused bool `json:"paused,omitempty"`
+}
+
+// Result concludes the game, it is attached to the last round.
+type Result struct {
+	Decision Decision `json:"decision"`
+	Winner   string   `json:"winner,omitempty"`
+	// Team is set when the last standing competitors belong to a team.
+	Team        string      `json:"team,omitempty"`
+	SuddenDeath SuddenDeath `json:"suddenDeath,omitempty"`
+	// Stats of every competitor who took part in the game by ID.
+	Stats map[string]*Stats `json:"stats,omitempty"`
+}
+
+// Stats of a competitor over the whole game.
+type Stats struct {
+	Name  string `json:"name"`
+	Team  string `json:"team,omitempty"`
+	Shots int    `json:"shots"`
+	Hits  int    `json:"hits"`
+	Dealt int    `json:"dealt"`
+	Taken int    `json:"taken"`
+	Kills int    `json:"kills"`
+	Alive bool   `json:"alive"`
 }
 
 type Shot struct {
 	From string `json:"from"`
 	To   string `json:"to"`
 }
+
+// Hit describes resolved shot, including missed ones, or other damage
+// competitor took, in which case outcome tells where it came from.
+type Hit struct {
+	From    string  `json:"from"`
+	To      string  `json:"to"`
+	Outcome Outcome `json:"outcome"`
+	Damage  int     `json:"damage"`
+	Killed  bool    `json:"killed,omitempty"`
+}
+
+type Overview struct {
+	Phase       Phase                  `json:"phase"`
+	Round       int                    `json:"round"`
+	Competitors map[string]*Competitor `json:"competitors"`
+	Result      *Result                `json:"result,omitempty"`
+	Arena       *Arena                 `json:"arena,omitempty"`
+	// Seed of random number generator, game with the same seed and actions can be replayed.
+	Seed int64 `json:"seed"`
+}
@@ -0,0 +1,148 @@
+package shootout
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/damejeras/shootout/internal/app"
+)
+
+const (
+	EffectBleeding EffectKind = "bleeding"
+	EffectStunned  EffectKind = "stunned"
+	EffectShield   EffectKind = "shield"
+)
+
+var (
+	ErrStunned = fmt.Errorf("competitor is stunned")
+	ErrNoItem  = fmt.Errorf("competitor does not have the item")
+)
+
+type EffectKind string
+
+// Effect persists across rounds. Bleeding inflicts its amount of damage every
+// round, stunned competitor can not act and shield absorbs its amount of damage.
+// Effects are replaced instead of updated in place, because copies of
+// competitors handed out of the state share them.
+type Effect struct {
+	Kind EffectKind `json:"kind"`
+	// Rounds left after the current one.
+	Rounds int `json:"rounds"`
+	Amount int `json:"amount,omitempty"`
+
+	// fresh effect was applied during the last round and is not ticked yet
+	fresh bool
+	// source is ID of competitor who applied the effect
+	source string
+}
+
+func newEffect(cfg *app.Effect, source string) *Effect {
+	return &Effect{
+		Kind:   EffectKind(cfg.Kind),
+		Rounds: cfg.Rounds,
+		Amount: cfg.Amount,
+		fresh:  true,
+		source: source,
+	}
+}
+
+// affect applies effect to competitor, replacing effect of the same kind.
+func affect(competitor *Competitor, effect *Effect) {
+	effects := []*Effect{effect}
+	for _, applied := range competitor.Effects {
+		if applied.Kind != effect.Kind {
+			effects = append(effects, applied)
+		}
+	}
+
+	competitor.Effects = effects
+}
+
+func affected(competitor *Competitor, kind EffectKind) bool {
+	for _, effect := range competitor.Effects {
+		if effect.Kind == kind {
+			return true
+		}
+	}
+
+	return false
+}
+
+// tickEffects makes bleeding competitors lose health and expires effects.
+func (s *State) tickEffects() {
+	for _, competitor := range s.competitors {
+		var effects, bleeding []*Effect
+		for _, effect := range competitor.Effects {
+			ticked := *effect
+			if ticked.fresh {
+				ticked.fresh = false
+				effects = append(effects, &ticked)
+				continue
+			}
+
+			if ticked.Kind == EffectBleeding {
+				bleeding = append(bleeding, &ticked)
+			}
+
+			if ticked.Rounds--; ticked.Rounds > 0 {
+				effects = append(effects, &ticked)
+			}
+		}
+
+		competitor.Effects = effects
+
+		for _, effect := range bleeding {
+			if s.Inflict(competitor, effect.source, OutcomeBleeding, effect.Amount) {
+				log.Printf("🩸 %s bled out 🩸", competitor.Name)
+				break
+			}
+		}
+	}
+}
+
+// absorb damage with competitor's shield, returns damage left.
+func absorb(competitor *Competitor, damage int) int {
+	var effects []*Effect
+	for _, effect := range competitor.Effects {
+		if effect.Kind != EffectShield || damage == 0 {
+			effects = append(effects, effect)
+			continue
+		}
+
+		absorbed := damage
+		if absorbed > effect.Amount {
+			absorbed = effect.Amount
+		}
+
+		damage -= absorbed
+
+		if absorbed < effect.Amount {
+			shield := *effect
+			shield.Amount -= absorbed
+			effects = append(effects, &shield)
+		}
+	}
+
+	competitor.Effects = effects
+
+	return damage
+}
+
+// use one of competitor's items, item applies its effect to the competitor.
+func (s *State) use(competitor *Competitor, action *Action) error {
+	for i, item := range competitor.Items {
+		if item != action.Item {
+			continue
+		}
+
+		items := make([]string, 0, len(competitor.Items)-1)
+		competitor.Items = append(append(items, competitor.Items[:i]...), competitor.Items[i+1:]...)
+		affect(competitor, newEffect(s.items[item], competitor.ID))
+
+		log.Printf("🎒 %s used %s 🎒", competitor.Name, item)
+
+		return nil
+	}
+
+	return ErrNoItem
+}
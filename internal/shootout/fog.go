@@ -0,0 +1,107 @@
+package shootout
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/damejeras/shootout/internal/infrastructure"
+)
+
+const (
+	ConditionHealthy  Condition = "healthy"
+	ConditionWounded  Condition = "wounded"
+	ConditionCritical Condition = "critical"
+)
+
+// Condition is health bucket of the competitor, relative to starting health.
+type Condition string
+
+// Views personalize round event for every competitor ever registered, so
+// eliminated ones learn about it too. Enemies' health is shown as condition,
+// their damage is hidden until they shoot, their other stats, ammo and items
+// are hidden for good and damage of hits viewer's side was not part of is
+// hidden as well. What enemies visibly do, their weapon, position, cover,
+// aim and effects, is seen. Teammates see everything of each other.
+func (s *State) Views(event *infrastructure.Event) (map[string]*infrastructure.Event, error) {
+	var round Round
+	if err := json.Unmarshal(event.Data, &round); err != nil {
+		return nil, fmt.Errorf("unmarshal round: %w", err)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	views := make(map[string]*infrastructure.Event, len(s.stats))
+	for viewer := range s.stats {
+		view, err := infrastructure.NewEvent(event.Type, s.view(&round, viewer))
+		if err != nil {
+			return nil, err
+		}
+
+		views[viewer] = view
+	}
+
+	return views, nil
+}
+
+func (s *State) view(round *Round, viewer string) *Round {
+	team := s.stats[viewer].Team
+	ally := func(id string) bool {
+		return id == viewer || (team != "" && s.stats[id] != nil && s.stats[id].Team == team)
+	}
+
+	view := &Round{
+		Competitors: make(map[string]*Competitor, len(round.Competitors)),
+		Result:      round.Result,
+		Arena:       round.Arena,
+	}
+
+	for id, competitor := range round.Competitors {
+		if ally(id) {
+			view.Competitors[id] = competitor
+			continue
+		}
+
+		concealed := Competitor{
+			ID:        competitor.ID,
+			Name:      competitor.Name,
+			Team:      competitor.Team,
+			Weapon:    competitor.Weapon,
+			InCover:   competitor.InCover,
+			Aiming:    competitor.Aiming,
+			Position:  competitor.Position,
+			Effects:   competitor.Effects,
+			Condition: condition(competitor.Health, s.vitality[id]),
+		}
+
+		if s.stats[id].Shots > 0 {
+			concealed.Damage = competitor.Damage
+		}
+
+		view.Competitors[id] = &concealed
+	}
+
+	for _, hit := range round.Feed {
+		if ally(hit.From) || ally(hit.To) {
+			view.Feed = append(view.Feed, hit)
+			continue
+		}
+
+		concealed := *hit
+		concealed.Damage = 0
+		view.Feed = append(view.Feed, &concealed)
+	}
+
+	return view
+}
+
+func condition(health, vitality int) Condition {
+	switch {
+	case health*3 > vitality*2:
+		return ConditionHealthy
+	case health*3 > vitality:
+		return ConditionWounded
+	default:
+		return ConditionCritical
+	}
+}
@@ -0,0 +1,149 @@
+package shootout
+
+import "fmt"
+
+const defaultMinCompetitors = 2
+
+var (
+	ErrUnknownCompetitor    = fmt.Errorf("unknown competitor")
+	ErrNotEnoughCompetitors = fmt.Errorf("not enough competitors")
+	ErrNotRunning           = fmt.Errorf("shootout is not running")
+	ErrNotPaused            = fmt.Errorf("shootout is not paused")
+)
+
+// Overview describes state for management purposes. Competitors are copied,
+// so overview can be used after lock is released.
+func (s *State) Overview() *Overview {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	competitors := make(map[string]*Competitor, len(s.competitors))
+	for id, competitor := range s.competitors {
+		competitor := *competitor
+		competitors[id] = &competitor
+	}
+
+	return &Overview{
+		Phase:       s.phase,
+		Round:       s.round,
+		Competitors: competitors,
+		Result:      s.result,
+		Seed:        s.combat.seed,
+		Arena:       s.arena,
+	}
+}
+
+// Stats of every competitor ever registered, copied like in overview.
+func (s *State) Stats() map[string]*Stats {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	stats := make(map[string]*Stats, len(s.stats))
+	for id, competitorStats := range s.stats {
+		competitorStats := *competitorStats
+		_, competitorStats.Alive = s.competitors[id]
+		stats[id] = &competitorStats
+	}
+
+	return stats
+}
+
+func (s *State) Competitor(id string) (*Competitor, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	competitor, ok := s.competitors[id]
+	if !ok {
+		return nil, ErrUnknownCompetitor
+	}
+
+	copied := *competitor
+
+	return &copied, nil
+}
+
+// Remove takes competitor out of the game, as if they forfeited. Removed
+// competitor is forgotten along with its stats, so its name and key are free.
+func (s *State) Remove(id string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.phase == PhaseFinished {
+		return ErrFinished
+	}
+
+	if _, ok := s.competitors[id]; !ok {
+		return ErrUnknownCompetitor
+	}
+
+	delete(s.competitors, id)
+	delete(s.stats, id)
+	delete(s.vitality, id)
+	delete(s.covering, id)
+	delete(s.acted, id)
+
+	for key, keyID := range s.keys {
+		if keyID == id {
+			delete(s.keys, key)
+		}
+	}
+
+	return nil
+}
+
+// Start begins shootout without waiting for all competitors to register.
+func (s *State) Start() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.phase != PhaseRegistration {
+		return ErrAlreadyStarted
+	}
+
+	if len(s.competitors) < s.minCompetitors {
+		return ErrNotEnoughCompetitors
+	}
+
+	s.phase = PhaseRunning
+
+	return nil
+}
+
+func (s *State) Pause() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.phase != PhaseRunning {
+		return ErrNotRunning
+	}
+
+	s.phase = PhasePaused
+
+	return nil
+}
+
+func (s *State) Resume() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.phase != PhasePaused {
+		return ErrNotPaused
+	}
+
+	s.phase = PhaseRunning
+
+	return nil
+}
+
+func (s *State) Abort() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.phase == PhaseFinished {
+		return ErrFinished
+	}
+
+	s.finish(DecisionAborted)
+
+	return nil
+}
@@ -0,0 +1,149 @@
+package shootout
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/damejeras/shootout/internal/app"
+)
+
+const DefaultRules = "standard"
+
+var ErrUnknownRules = fmt.Errorf("unknown ruleset")
+
+// Rules decide how the game is played. State runs the game: it accepts
+// registrations and actions, advances rounds and announces results, while
+// every decision is delegated to rules. Rules are called with state locked,
+// they inspect and alter the game with Round, Competitors, Sides and Inflict,
+// which do not lock it again.
+type Rules interface {
+	// Validate competitor's registration.
+	Validate(s *State, competitor *Competitor) error
+	// Apply competitor's action, competitor acts once per round.
+	Apply(s *State, competitor *Competitor, action *Action) error
+	// EndRound processes the game between rounds.
+	EndRound(s *State)
+	// Victory decides whether the game is over, returns nil while it is not.
+	Victory(s *State) *Result
+}
+
+// ShotRules are implemented by rules which adjust damage of resolved shots.
+type ShotRules interface {
+	// Shot returns damage target takes.
+	Shot(s *State, shooter, target *Competitor, outcome Outcome, damage int) int
+}
+
+// Round is the number of the current round.
+func (s *State) Round() int {
+	return s.round
+}
+
+// Competitors still in the game by ID, rules can alter them.
+func (s *State) Competitors() map[string]*Competitor {
+	return s.competitors
+}
+
+// RulesFactory creates ruleset for the game.
+type RulesFactory func(cfg *app.ArbiterConfig) Rules
+
+var (
+	rulesets = map[string]RulesFactory{
+		DefaultRules: func(*app.ArbiterConfig) Rules { return StandardRules{} },
+	}
+	rulesetsLock sync.RWMutex
+)
+
+// RegisterRules makes ruleset available by name, so variants of the game can
+// be selected in configuration.
+func RegisterRules(name string, factory RulesFactory) {
+	rulesetsLock.Lock()
+	defer rulesetsLock.Unlock()
+
+	rulesets[name] = factory
+}
+
+// Ruleset looks up ruleset by name, empty name stands for default ruleset.
+func Ruleset(name string) (RulesFactory, error) {
+	rulesetsLock.RLock()
+	defer rulesetsLock.RUnlock()
+
+	if name == "" {
+		name = DefaultRules
+	}
+
+	factory, ok := rulesets[name]
+	if !ok {
+		return nil, fmt.Errorf("%w %q, available rulesets: %v", ErrUnknownRules, name, rulesetNames())
+	}
+
+	return factory, nil
+}
+
+func rulesetNames() []string {
+	names := make([]string, 0, len(rulesets))
+	for name := range rulesets {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// StandardRules is the default ruleset, variants can embed it and override
+// only the rules they change.
+type StandardRules struct{}
+
+func (StandardRules) Validate(s *State, competitor *Competitor) error {
+	return s.limits.validate(competitor)
+}
+
+func (StandardRules) Apply(s *State, competitor *Competitor, action *Action) error {
+	rule, ok := actionRules[action.Kind]
+	if !ok {
+		return ErrUnacceptablePayload
+	}
+
+	if affected(competitor, EffectStunned) {
+		return ErrStunned
+	}
+
+	return rule(s, competitor, action)
+}
+
+func (StandardRules) EndRound(s *State) {
+	s.takeCover()
+	s.tickEffects()
+
+	for _, competitor := range s.competitors {
+		s.armory.tick(competitor)
+	}
+
+	s.shrink()
+}
+
+func (StandardRules) Victory(s *State) *Result {
+	if s.suddenDeathRounds() > 0 && s.suddenDeath.rule == SuddenDeathHealth {
+		return s.healthiest()
+	}
+
+	if s.Sides() > 1 {
+		return nil
+	}
+
+	result := &Result{Decision: DecisionLastStanding}
+	if s.suddenDeathRounds() > 0 {
+		result.Decision = DecisionSuddenDeath
+		result.SuddenDeath = s.suddenDeath.rule
+	}
+
+	for id, competitor := range s.competitors {
+		result.Team = competitor.Team
+		if len(s.competitors) == 1 {
+			result.Winner = id
+		}
+	}
+
+	return result
+}
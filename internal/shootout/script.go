@@ -0,0 +1,268 @@
+package shootout
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/damejeras/shootout/internal/app"
+	"go.starlark.net/starlark"
+)
+
+const (
+	scriptName           = "rules.star"
+	defaultScriptSteps   = 100000
+	defaultScriptTimeout = 100 * time.Millisecond
+	// maxScriptSize of the source and maxScriptString of strings returned by
+	// hooks coarsely bound what script takes in and gives out.
+	maxScriptSize   = 64 << 10
+	maxScriptString = 256
+	hookOnShot      = "on_shot"
+	hookOnRoundEnd  = "on_round_end"
+	hookIsFinished  = "is_finished"
+)
+
+var ErrInvalidScript = fmt.Errorf("invalid rules script")
+
+// ScriptRules adjust underlying rules with hooks defined by Starlark script:
+//
+//	on_shot(game, shot) returns damage target takes, None keeps it.
+//	on_round_end(game) returns health change by competitor ID.
+//	is_finished(game) returns True or a dict with winner and team to end
+//	the game, falsy value leaves the decision to underlying rules.
+//
+// Script can not load modules or access anything outside of the game and
+// every call is limited in execution steps and time. Failing hooks are logged
+// and ignored, so broken script does not halt the game.
+type ScriptRules struct {
+	Rules
+	steps      uint64
+	timeout    time.Duration
+	onShot     starlark.Callable
+	onRoundEnd starlark.Callable
+	isFinished starlark.Callable
+}
+
+func NewScriptRules(cfg *app.ArbiterConfig, rules Rules) (*ScriptRules, error) {
+	script := &ScriptRules{
+		Rules:   rules,
+		steps:   defaultScriptSteps,
+		timeout: defaultScriptTimeout,
+	}
+
+	if cfg.RulesScriptSteps > 0 {
+		script.steps = uint64(cfg.RulesScriptSteps)
+	}
+
+	if cfg.RulesScriptTimeout > 0 {
+		script.timeout = cfg.RulesScriptTimeout
+	}
+
+	if len(cfg.RulesScript) > maxScriptSize {
+		return nil, fmt.Errorf("%w: source exceeds %d bytes", ErrInvalidScript, maxScriptSize)
+	}
+
+	thread, stop := script.thread(scriptName)
+	defer stop()
+
+	globals, err := starlark.ExecFile(thread, scriptName, cfg.RulesScript, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidScript, err)
+	}
+
+	hooks := map[string]*starlark.Callable{
+		hookOnShot:     &script.onShot,
+		hookOnRoundEnd: &script.onRoundEnd,
+		hookIsFinished: &script.isFinished,
+	}
+
+	for name, hook := range hooks {
+		value, ok := globals[name]
+		if !ok {
+			continue
+		}
+
+		callable, ok := value.(starlark.Callable)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s is not a function", ErrInvalidScript, name)
+		}
+
+		*hook = callable
+	}
+
+	return script, nil
+}
+
+func (r *ScriptRules) Shot(s *State, shooter, target *Competitor, outcome Outcome, damage int) int {
+	if shot, ok := r.Rules.(ShotRules); ok {
+		damage = shot.Shot(s, shooter, target, outcome, damage)
+	}
+
+	if r.onShot == nil {
+		return damage
+	}
+
+	shot := starlark.NewDict(4)
+	_ = shot.SetKey(starlark.String("from"), starlark.String(shooter.ID))
+	_ = shot.SetKey(starlark.String("to"), starlark.String(target.ID))
+	_ = shot.SetKey(starlark.String("outcome"), starlark.String(outcome))
+	_ = shot.SetKey(starlark.String("damage"), starlark.MakeInt(damage))
+
+	value, err := r.call(hookOnShot, r.onShot, game(s), shot)
+	if err != nil || value == starlark.None {
+		return damage
+	}
+
+	adjusted, err := starlark.AsInt32(value)
+	if err != nil {
+		log.Printf("rules script %s: %v", hookOnShot, err)
+		return damage
+	}
+
+	if adjusted < 0 {
+		return 0
+	}
+
+	return adjusted
+}
+
+func (r *ScriptRules) EndRound(s *State) {
+	r.Rules.EndRound(s)
+
+	if r.onRoundEnd == nil {
+		return
+	}
+
+	value, err := r.call(hookOnRoundEnd, r.onRoundEnd, game(s))
+	if err != nil || value == starlark.None {
+		return
+	}
+
+	changes, ok := value.(*starlark.Dict)
+	if !ok {
+		log.Printf("rules script %s: expected dict, got %s", hookOnRoundEnd, value.Type())
+		return
+	}
+
+	if changes.Len() > len(s.Competitors()) {
+		log.Printf("rules script %s: %d changes for %d competitors", hookOnRoundEnd, changes.Len(), len(s.Competitors()))
+		return
+	}
+
+	for _, item := range changes.Items() {
+		id, ok := starlark.AsString(item[0])
+		if !ok {
+			continue
+		}
+
+		competitor, ok := s.Competitors()[id]
+		if !ok {
+			continue
+		}
+
+		change, err := starlark.AsInt32(item[1])
+		if err != nil {
+			log.Printf("rules script %s: %v", hookOnRoundEnd, err)
+			continue
+		}
+
+		if change >= 0 {
+			competitor.Health += change
+			continue
+		}
+
+		if s.Inflict(competitor, "", OutcomeScripted, -change) {
+			log.Printf("📜 %s was eliminated by the rules 📜", competitor.Name)
+		}
+	}
+}
+
+func (r *ScriptRules) Victory(s *State) *Result {
+	if r.isFinished == nil {
+		return r.Rules.Victory(s)
+	}
+
+	value, err := r.call(hookIsFinished, r.isFinished, game(s))
+	if err != nil || !value.Truth() {
+		return r.Rules.Victory(s)
+	}
+
+	result := &Result{Decision: DecisionScripted}
+
+	if decision, ok := value.(*starlark.Dict); ok {
+		result.Winner = scriptString(decision, "winner")
+		result.Team = scriptString(decision, "team")
+	}
+
+	return result
+}
+
+func (r *ScriptRules) call(name string, hook starlark.Callable, args ...starlark.Value) (starlark.Value, error) {
+	thread, stop := r.thread(name)
+	defer stop()
+
+	value, err := starlark.Call(thread, hook, args, nil)
+	if err != nil {
+		log.Printf("rules script %s: %v", name, err)
+	}
+
+	return value, err
+}
+
+// thread is a sandbox for a single script execution, it has no load
+// support, runs limited number of steps and is cancelled after timeout
+// unless stopped.
+func (r *ScriptRules) thread(name string) (*starlark.Thread, func() bool) {
+	thread := &starlark.Thread{
+		Name: name,
+		Print: func(_ *starlark.Thread, msg string) {
+			log.Printf("📜 %s 📜", msg)
+		},
+	}
+
+	thread.SetMaxExecutionSteps(r.steps)
+	timer := time.AfterFunc(r.timeout, func() {
+		thread.Cancel("timeout")
+	})
+
+	return thread, timer.Stop
+}
+
+// game describes state to the script, values are frozen so script can not
+// alter them.
+func game(s *State) *starlark.Dict {
+	competitors := starlark.NewDict(len(s.Competitors()))
+	for id, competitor := range s.Competitors() {
+		value := starlark.NewDict(8)
+		_ = value.SetKey(starlark.String("id"), starlark.String(competitor.ID))
+		_ = value.SetKey(starlark.String("name"), starlark.String(competitor.Name))
+		_ = value.SetKey(starlark.String("team"), starlark.String(competitor.Team))
+		_ = value.SetKey(starlark.String("health"), starlark.MakeInt(competitor.Health))
+		_ = value.SetKey(starlark.String("damage"), starlark.MakeInt(competitor.Damage))
+		_ = value.SetKey(starlark.String("accuracy"), starlark.MakeInt(competitor.Accuracy))
+		_ = value.SetKey(starlark.String("armor"), starlark.MakeInt(competitor.Armor))
+		_ = value.SetKey(starlark.String("speed"), starlark.MakeInt(competitor.Speed))
+		_ = competitors.SetKey(starlark.String(id), value)
+	}
+
+	result := starlark.NewDict(2)
+	_ = result.SetKey(starlark.String("round"), starlark.MakeInt(s.Round()))
+	_ = result.SetKey(starlark.String("competitors"), competitors)
+	result.Freeze()
+
+	return result
+}
+
+func scriptString(dict *starlark.Dict, key string) string {
+	value, ok, err := dict.Get(starlark.String(key))
+	if err != nil || !ok {
+		return ""
+	}
+
+	str, _ := starlark.AsString(value)
+	if len(str) > maxScriptString {
+		return ""
+	}
+
+	return str
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"sync"
+	"time"
 
 	"github.com/damejeras/shootout/internal/app"
 	"github.com/damejeras/shootout/internal/infrastructure"
@@ -16,42 +17,153 @@ var (
 	ErrUnacceptablePayload = fmt.Errorf("unacceptable payload")
 	ErrFinished            = fmt.Errorf("shootout is finished")
 	ErrInvalidRegistration = fmt.Errorf("invalid registration event")
+	ErrPaused              = fmt.Errorf("shootout is paused")
+	ErrEliminated          = fmt.Errorf("competitor is eliminated")
 )
 
 type State struct {
-	started, finished bool
-	playerNumber      int
+	phase          Phase
+	round          int
+	idle           int
+	playerNumber   int
+	minCompetitors int
+	deadline       time.Time
+	limits         *Limits
+	combat         *combat
+	armory         armory
+	friendlyFire   bool
+	penalty        int
+	turnBased      bool
+	tactics        *tactics
+	arena          *Arena
+	items          map[string]*app.Effect
+	suddenDeath    *suddenDeath
+	rules          Rules
+	result         *Result
+	announced      bool
 
 	competitors map[string]*Competitor
+	keys        map[string]string
+	stats       map[string]*Stats
+	vitality    map[string]int
+	covering    map[string]bool
+	acted       map[string]bool
+	feed        []*Hit
 	lock        *sync.Mutex
 }
 
 func NewState(cfg *app.ArbiterConfig) *State {
-	return &State{
-		playerNumber: cfg.Competitors,
-		competitors:  make(map[string]*Competitor),
-		lock:         new(sync.Mutex),
+	state := &State{
+		phase:          PhaseRegistration,
+		playerNumber:   cfg.Competitors,
+		minCompetitors: cfg.MinCompetitors,
+		limits:         newLimits(cfg),
+		combat:         newCombat(cfg),
+		armory:         cfg.Armory,
+		friendlyFire:   cfg.FriendlyFire,
+		penalty:        cfg.FriendlyFirePenalty,
+		turnBased:      cfg.TurnBased,
+		tactics:        newTactics(cfg),
+		arena:          newArena(cfg),
+		items:          cfg.ItemEffects,
+		suddenDeath:    newSuddenDeath(cfg),
+		competitors:    make(map[string]*Competitor),
+		keys:           make(map[string]string),
+		stats:          make(map[string]*Stats),
+		vitality:       make(map[string]int),
+		covering:       make(map[string]bool),
+		acted:          make(map[string]bool),
+		lock:           new(sync.Mutex),
 	}
+
+	factory, err := Ruleset(cfg.Rules)
+	if err != nil {
+		// configuration is validated before the state is created
+		factory = rulesets[DefaultRules]
+	}
+
+	state.rules = factory(cfg)
+
+	if cfg.RulesScript != "" {
+		rules, err := NewScriptRules(cfg, state.rules)
+		if err != nil {
+			// configuration is validated before the state is created
+			log.Printf("load rules script: %v", err)
+		} else {
+			state.rules = rules
+		}
+	}
+
+	if state.minCompetitors == 0 {
+		state.minCompetitors = defaultMinCompetitors
+	}
+
+	if cfg.RegistrationTimeout > 0 {
+		state.deadline = time.Now().Add(cfg.RegistrationTimeout)
+	}
+
+	return state
 }
 
 func (s *State) Emit() (*infrastructure.Event, error) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
-	if s.finished {
-		return nil, ErrFinished
+	if s.phase == PhaseRegistration && !s.deadline.IsZero() && time.Now().After(s.deadline) {
+		if len(s.competitors) >= s.minCompetitors {
+			s.phase = PhaseRunning
+		} else {
+			s.finish(DecisionInsufficientPlayers)
+		}
 	}
 
-	if !s.started {
+	switch s.phase {
+	case PhaseRegistration:
 		return infrastructure.NewEvent(infrastructure.EventHeartbeat, nil)
+	case PhasePaused:
+		return infrastructure.NewEvent(infrastructure.EventHeartbeat, &Heartbeat{Paused: true})
+	case PhaseRunning:
+		s.round++
+		s.idle++
+		s.acted = make(map[string]bool)
+		s.rules.EndRound(s)
+
+		if result := s.rules.Victory(s); result != nil {
+			s.conclude(result)
+		}
+	case PhaseFinished:
+		// game can be finished between emissions, e.g. aborted, result still has to be announced
+		if s.announced {
+			return nil, ErrFinished
+		}
 	}
 
-	if len(s.competitors) == 1 {
-		s.finished = true
+	feed := s.feed
+	s.feed = nil
+	s.announced = s.phase == PhaseFinished
+
+	return infrastructure.NewEvent(infrastructure.EventRound, &Round{
+		Competitors: s.competitors,
+		Feed:        feed,
+		Result:      s.result,
+		Arena:       s.arena,
+	})
+}
+
+// catch up before the next emission. Snapshot of finished game carries result.
+// catch up before the next emission.
+func (s *State) Snapshot() (*infrastructure.Event, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.phase == PhaseRegistration {
+		return infrastructure.NewEvent(infrastructure.EventHeartbeat, nil)
 	}
 
 	return infrastructure.NewEvent(infrastructure.EventRound, &Round{
 		Competitors: s.competitors,
+		Result:      s.result,
+		Arena:       s.arena,
 	})
 }
 
@@ -64,72 +176,245 @@ func (s *State) Handle(event *infrastructure.Event) error {
 		return s.handleRegistration(event)
 	case infrastructure.EventShot:
 		return s.handleShot(event)
+	case infrastructure.EventAction:
+		return s.handleAction(event)
 	default:
 		return nil
 	}
 }
 
+// Register adds competitor to the game. Registration with a key is idempotent:
+// repeated registration with the same key returns already registered
+// competitor, which also lets restarted shooters rejoin the game while their
+// competitor is alive.
+func (s *State) Register(registration *Registration) (*Competitor, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.register(registration)
+}
+
 func (s *State) handleRegistration(event *infrastructure.Event) error {
-	if s.started {
-		return ErrAlreadyStarted
+	var registration Registration
+	if err := json.Unmarshal(event.Data, &registration); err != nil {
+		return fmt.Errorf("unmarshal registration payload: %w", err)
 	}
 
-	var competitor Competitor
-	if err := json.Unmarshal(event.Data, &competitor); err != nil {
-		return fmt.Errorf("unmarshal registration payload: %w", err)
+	_, err := s.register(&registration)
+
+	return err
+}
+
+func (s *State) register(registration *Registration) (*Competitor, error) {
+	key := registration.Key
+	id, rejoin := s.keys[key]
+	rejoin = rejoin && key != ""
+
+	// name stays taken after elimination, it identifies competitor in results
+	if !rejoin && s.named(registration.Name) {
+		return nil, &ValidationError{Violations: []*Violation{{Field: "name", Reason: "is already taken"}}}
+	}
+
+	if rejoin {
+		if s.phase == PhaseFinished {
+			return nil, ErrFinished
+		}
+
+		competitor, ok := s.competitors[id]
+		if !ok {
+			return nil, ErrEliminated
+		}
+
+		copied := *competitor
+
+		return &copied, nil
 	}
 
-	if competitor.IsZero() {
-		return ErrInvalidRegistration
+	if s.phase != PhaseRegistration {
+		return nil, ErrAlreadyStarted
 	}
 
+	if registration.Competitor.IsZero() {
+		return nil, ErrInvalidRegistration
+	}
+
+	if err := s.rules.Validate(s, &registration.Competitor); err != nil {
+		return nil, err
+	}
+
+	competitor := registration.Competitor
+	if err := s.spawn(&competitor); err != nil {
+		return nil, err
+	}
+
+	s.armory.equip(&competitor)
 	s.competitors[competitor.ID] = &competitor
+	if key != "" {
+		s.keys[key] = competitor.ID
+	}
+	s.stats[competitor.ID] = &Stats{Name: competitor.Name, Team: competitor.Team}
+	s.vitality[competitor.ID] = competitor.Health
 
 	if len(s.competitors) == s.playerNumber {
-		s.started = true
+		s.phase = PhaseRunning
 	}
 
-	return nil
+	copied := competitor
+
+	return &copied, nil
 }
 
-func (s *State) handleShot(event *infrastructure.Event) error {
-	if !s.started {
-		return ErrNotStarted
+func (s *State) named(name string) bool {
+	for _, stats := range s.stats {
+		if stats.Name == name {
+			return true
+		}
 	}
 
+	return false
+}
+
+// handleShot handles shot event, which is a shoot action of shooters that
+// predate actions.
+func (s *State) handleShot(event *infrastructure.Event) error {
 	var shot Shot
 	if err := json.Unmarshal(event.Data, &shot); err != nil {
 		return fmt.Errorf("unmarshal shot payload: %w", err)
 	}
 
-	if shot.From == "" || shot.To == "" {
+	return s.act(&Action{Kind: ActionShoot, From: shot.From, To: shot.To})
+}
+
+func (s *State) shoot(shooter *Competitor, shot *Action) error {
+	if shot.To == "" {
 		return ErrUnacceptablePayload
 	}
 
-	_, ok := s.competitors[shot.From]
+	target, ok := s.competitors[shot.To]
 	if !ok {
 		// too late, sorry
 		return nil
 	}
 
-	_, ok = s.competitors[shot.To]
-	if !ok {
-		// too late, sorry
-		return nil
+	if teammates(shooter, target) && !s.friendlyFire {
+		return ErrFriendlyFire
+	}
+
+	if err := s.aimAt(shooter, target); err != nil {
+		return err
+	}
+
+	if err := s.armory.trigger(shooter); err != nil {
+		return err
+	}
+
+	bonus := s.armory.damage(shooter, s.combat)
+	if shooter.Aiming {
+		bonus += (shooter.Damage + bonus) * s.tactics.aimBonus / 100
+		shooter.Aiming = false
+	}
+
+	outcome, damage := s.combat.resolve(shooter, target, bonus)
+	if s.arena != nil {
+		damage = s.arena.falloff(damage, s.arena.Distance(*shooter.Position, *target.Position))
+	}
+
+	damage = s.escalate(damage)
+
+	if target.InCover {
+		damage = halve(damage)
+	}
+
+	damage = absorb(target, damage)
+
+	if rules, ok := s.rules.(ShotRules); ok {
+		damage = rules.Shot(s, shooter, target, outcome, damage)
+	}
+
+	target.Health -= damage
+
+	hit := &Hit{
+		From:    shot.From,
+		To:      shot.To,
+		Outcome: outcome,
+		Damage:  damage,
+	}
+	s.feed = append(s.feed, hit)
+	s.record(hit)
+
+	switch outcome {
+	case OutcomeMiss:
+		log.Printf("💨 %s missed %s 💨", shooter.Name, target.Name)
+	case OutcomeCritical:
+		log.Printf("💥 %s inflicted %d critical damage for %s 💥", shooter.Name, damage, target.Name)
+	default:
+		log.Printf("🔫 %s inflicted %d damage for %s 🔫", shooter.Name, damage, target.Name)
 	}
 
-	s.competitors[shot.To].Health -= s.competitors[shot.From].Damage
+	if effect := s.armory.effect(shooter); effect != nil && outcome != OutcomeMiss {
+		affect(target, newEffect(effect, shooter.ID))
+	}
 
-	log.Printf(
-		"🔫 %s inflicted %d damage for %s 🔫",
-		s.competitors[shot.From].Name,
-		s.competitors[shot.From].Damage,
-		s.competitors[shot.To].Name,
-	)
+	if target.Health < 1 {
+		s.kill(hit)
+	}
 
-	if s.competitors[shot.To].Health < 1 {
-		delete(s.competitors, shot.To)
+	if teammates(shooter, target) {
+		s.punish(shooter, damage)
 	}
 
 	return nil
 }
+
+// Inflict damage competitor takes besides shots, damage is credited to
+// whoever caused it. Returns whether competitor was eliminated.
+func (s *State) Inflict(competitor *Competitor, from string, outcome Outcome, damage int) bool {
+	competitor.Health -= damage
+	s.stats[competitor.ID].Taken += damage
+
+	if stats, ok := s.stats[from]; ok && from != competitor.ID {
+		stats.Dealt += damage
+	}
+
+	hit := &Hit{
+		From:    from,
+		To:      competitor.ID,
+		Outcome: outcome,
+		Damage:  damage,
+	}
+	s.feed = append(s.feed, hit)
+
+	if competitor.Health < 1 {
+		s.kill(hit)
+		return true
+	}
+
+	return false
+}
+
+// kill eliminates target of the fatal hit, kill is credited to whoever
+// caused it.
+func (s *State) kill(hit *Hit) {
+	hit.Killed = true
+
+	if stats, ok := s.stats[hit.From]; ok && hit.From != hit.To {
+		stats.Kills++
+	}
+
+	delete(s.competitors, hit.To)
+}
+
+func (s *State) finish(decision Decision) {
+	s.conclude(&Result{Decision: decision})
+}
+
+// conclude the game with result, attaching stats of every competitor.
+func (s *State) conclude(result *Result) {
+	s.phase = PhaseFinished
+	s.result = result
+	s.result.Stats = s.stats
+
+	for id, stats := range s.stats {
+		_, stats.Alive = s.competitors[id]
+	}
+}
@@ -2,8 +2,13 @@ package shootout
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/JeremyLoy/config"
 	"github.com/damejeras/shootout/internal/app"
 	"github.com/damejeras/shootout/internal/infrastructure"
 )
@@ -260,3 +265,1651 @@ func TestStateShotEvent(t *testing.T) {
 		t.Fatalf("unexpected change in competitors' health")
 	}
 }
+
+func TestStateFeed(t *testing.T) {
+	state := NewState(&app.ArbiterConfig{
+		Competitors: 2,
+	})
+
+	snapshot, err := state.Snapshot()
+	if err != nil {
+		t.Fatalf("unexpected snapshot err: %v", err)
+	}
+
+	if snapshot.Type != infrastructure.EventHeartbeat {
+		t.Fatalf("snapshot before start should be of type %q, got %q", infrastructure.EventHeartbeat, snapshot.Type)
+	}
+
+	for _, competitor := range []*Competitor{
+		{ID: "test_1", Name: "Test1", Health: 3, Damage: 1},
+		{ID: "test_2", Name: "Test2", Health: 1, Damage: 1},
+	} {
+		registration, _ := infrastructure.NewEvent(infrastructure.EventRegistration, competitor)
+		if err := state.Handle(registration); err != nil {
+			t.Fatalf("unexpected registration err: %v", err)
+		}
+	}
+
+	shot, _ := infrastructure.NewEvent(infrastructure.EventShot, &Shot{From: "test_2", To: "test_1"})
+	if err := state.Handle(shot); err != nil {
+		t.Fatalf("unexpected shot err: %v", err)
+	}
+
+	shot, _ = infrastructure.NewEvent(infrastructure.EventShot, &Shot{From: "test_1", To: "test_2"})
+	if err := state.Handle(shot); err != nil {
+		t.Fatalf("unexpected shot err: %v", err)
+	}
+
+	snapshot, err = state.Snapshot()
+	if err != nil {
+		t.Fatalf("unexpected snapshot err: %v", err)
+	}
+
+	var snapshotRound Round
+	if err := json.Unmarshal(snapshot.Data, &snapshotRound); err != nil {
+		t.Fatalf("can not unmarshal snapshot: %v", err)
+	}
+
+	if len(snapshotRound.Feed) != 0 || len(snapshotRound.Competitors) != 1 {
+		t.Fatalf("snapshot should contain 1 competitor and no feed, got %d and %d", len(snapshotRound.Competitors), len(snapshotRound.Feed))
+	}
+
+	event, err := state.Emit()
+	if err != nil {
+		t.Fatalf("unexpected emission err: %v", err)
+	}
+
+	var round Round
+	if err := json.Unmarshal(event.Data, &round); err != nil {
+		t.Fatalf("can not unmarshal round event: %v", err)
+	}
+
+	if len(round.Feed) != 2 {
+		t.Fatalf("expected 2 hits in feed, got %d", len(round.Feed))
+	}
+
+	if round.Feed[0].Killed || round.Feed[0].Damage != 1 {
+		t.Fatalf("unexpected first hit: %+v", round.Feed[0])
+	}
+
+	if !round.Feed[1].Killed || round.Feed[1].To != "test_2" {
+		t.Fatalf("unexpected second hit: %+v", round.Feed[1])
+	}
+
+	snapshot, err = state.Snapshot()
+	if err != nil {
+		t.Fatalf("unexpected snapshot err after finish: %v", err)
+	}
+
+	var finalRound Round
+	if err := json.Unmarshal(snapshot.Data, &finalRound); err != nil {
+		t.Fatalf("can not unmarshal snapshot: %v", err)
+	}
+
+	if finalRound.Result == nil || finalRound.Result.Winner != "test_1" {
+		t.Fatalf("snapshot after finish should contain result, got %+v", finalRound.Result)
+	}
+}
+
+func TestStateManagement(t *testing.T) {
+	state := NewState(&app.ArbiterConfig{
+		Competitors: 3,
+	})
+
+	if err := state.Pause(); err != ErrNotRunning {
+		t.Fatalf("expected not running error when pausing before start, got: %v", err)
+	}
+
+	for _, competitor := range []*Competitor{
+		{ID: "test_1", Name: "Test1", Health: 3, Damage: 1},
+		{ID: "test_2", Name: "Test2", Health: 1, Damage: 1},
+	} {
+		registration, _ := infrastructure.NewEvent(infrastructure.EventRegistration, competitor)
+		if err := state.Handle(registration); err != nil {
+			t.Fatalf("unexpected registration err: %v", err)
+		}
+
+		if err := state.Start(); err != nil && competitor.ID == "test_2" {
+			t.Fatalf("unexpected forced start err: %v", err)
+		} else if err != ErrNotEnoughCompetitors && competitor.ID == "test_1" {
+			t.Fatalf("expected not enough competitors error, got: %v", err)
+		}
+	}
+
+	if overview := state.Overview(); overview.Phase != PhaseRunning || len(overview.Competitors) != 2 {
+		t.Fatalf("unexpected overview after forced start: %+v", overview)
+	}
+
+	if err := state.Pause(); err != nil {
+		t.Fatalf("unexpected pause err: %v", err)
+	}
+
+	event, err := state.Emit()
+	if err != nil {
+		t.Fatalf("unexpected emission err: %v", err)
+	}
+
+	if event.Type != infrastructure.EventHeartbeat {
+		t.Fatalf("paused shootout should emit %q, got %q", infrastructure.EventHeartbeat, event.Type)
+	}
+
+	if err := state.Resume(); err != nil {
+		t.Fatalf("unexpected resume err: %v", err)
+	}
+
+	if err := state.Resume(); err != ErrNotPaused {
+		t.Fatalf("expected not paused error, got: %v", err)
+	}
+
+	if _, err := state.Competitor("unknown"); err != ErrUnknownCompetitor {
+		t.Fatalf("expected unknown competitor error, got: %v", err)
+	}
+
+	if err := state.Remove("test_2"); err != nil {
+		t.Fatalf("unexpected removal err: %v", err)
+	}
+
+	if _, err := state.Competitor("test_2"); err != ErrUnknownCompetitor {
+		t.Fatalf("removed competitor should be unknown, got: %v", err)
+	}
+
+	if _, ok := state.Stats()["test_2"]; ok {
+		t.Fatalf("removed competitor should have no stats")
+	}
+
+	if _, err := state.Emit(); err != nil {
+		t.Fatalf("unexpected emission err: %v", err)
+	}
+
+	if overview := state.Overview(); overview.Phase != PhaseFinished || overview.Round != 1 {
+		t.Fatalf("expected shootout to finish after first round, got: %+v", overview)
+	}
+
+	if err := state.Abort(); err != ErrFinished {
+		t.Fatalf("expected finished error when aborting finished shootout, got: %v", err)
+	}
+
+	// competitor removed during registration frees its name and key
+	state = NewState(&app.ArbiterConfig{Competitors: 2})
+	registration := &Registration{Competitor: Competitor{ID: "test_1", Name: "Test1", Health: 3, Damage: 1}, Key: "secret"}
+	if _, err := state.Register(registration); err != nil {
+		t.Fatalf("unexpected registration err: %v", err)
+	}
+
+	if err := state.Remove("test_1"); err != nil {
+		t.Fatalf("unexpected removal err: %v", err)
+	}
+
+	registration.ID = "test_2"
+	competitor, err := state.Register(registration)
+	if err != nil {
+		t.Fatalf("unexpected registration err after removal: %v", err)
+	}
+
+	if competitor.ID != "test_2" {
+		t.Fatalf("key of removed competitor should not rejoin it, got %+v", competitor)
+	}
+
+	if stats := state.Stats(); len(stats) != 1 || stats["test_2"] == nil {
+		t.Fatalf("expected stats of the new competitor only, got %+v", stats)
+	}
+}
+
+func TestStatePauseAndAbort(t *testing.T) {
+	state := NewState(&app.ArbiterConfig{
+		Competitors: 2,
+	})
+
+	for _, competitor := range []*Competitor{
+		{ID: "test_1", Name: "Test1", Health: 3, Damage: 1},
+		{ID: "test_2", Name: "Test2", Health: 3, Damage: 1},
+	} {
+		registration, _ := infrastructure.NewEvent(infrastructure.EventRegistration, competitor)
+		if err := state.Handle(registration); err != nil {
+			t.Fatalf("unexpected registration err: %v", err)
+		}
+	}
+
+	if err := state.Pause(); err != nil {
+		t.Fatalf("unexpected pause err: %v", err)
+	}
+
+	event, err := state.Emit()
+	if err != nil {
+		t.Fatalf("unexpected emission err: %v", err)
+	}
+
+	var heartbeat Heartbeat
+	if err := json.Unmarshal(event.Data, &heartbeat); err != nil || !heartbeat.Paused {
+		t.Fatalf("expected paused heartbeat, got %s (err %v)", event.Data, err)
+	}
+
+	shot, _ := infrastructure.NewEvent(infrastructure.EventShot, &Shot{From: "test_1", To: "test_2"})
+	if err := state.Handle(shot); err != ErrPaused {
+		t.Fatalf("expected paused error for shot, got: %v", err)
+	}
+
+	if err := state.Abort(); err != nil {
+		t.Fatalf("unexpected abort err: %v", err)
+	}
+
+	if err := state.Handle(shot); err != ErrFinished {
+		t.Fatalf("expected finished error for shot after abort, got: %v", err)
+	}
+
+	event, err = state.Emit()
+	if err != nil {
+		t.Fatalf("unexpected emission err after abort: %v", err)
+	}
+
+	var round Round
+	if err := json.Unmarshal(event.Data, &round); err != nil {
+		t.Fatalf("can not unmarshal round event: %v", err)
+	}
+
+	if round.Result == nil || round.Result.Decision != DecisionAborted || round.Result.Winner != "" {
+		t.Fatalf("expected aborted result, got %+v", round.Result)
+	}
+
+	if len(round.Competitors) != 2 || round.Competitors["test_2"].Health != 3 {
+		t.Fatalf("unexpected competitors in aborted round: %+v", round.Competitors)
+	}
+
+	if _, err := state.Emit(); err != ErrFinished {
+		t.Fatalf("expected finished error after result, got: %v", err)
+	}
+}
+
+func TestStateRegistrationWindow(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		registered int
+		phase      Phase
+		result     *Result
+	}{
+		{name: "enough competitors", registered: 2, phase: PhaseRunning},
+		{name: "insufficient competitors", registered: 1, phase: PhaseFinished, result: &Result{Decision: DecisionInsufficientPlayers}},
+	} {
+		state := NewState(&app.ArbiterConfig{
+			Competitors:         3,
+			MinCompetitors:      2,
+			RegistrationTimeout: time.Nanosecond,
+		})
+
+		for i := 0; i < tc.registered; i++ {
+			registration, _ := infrastructure.NewEvent(infrastructure.EventRegistration, &Competitor{
+				ID:     fmt.Sprintf("test_%d", i),
+				Name:   fmt.Sprintf("Test%d", i),
+				Health: 3,
+				Damage: 1,
+			})
+			if err := state.Handle(registration); err != nil {
+				t.Fatalf("%s: unexpected registration err: %v", tc.name, err)
+			}
+		}
+
+		time.Sleep(time.Millisecond)
+
+		event, err := state.Emit()
+		if err != nil {
+			t.Fatalf("%s: unexpected emission err: %v", tc.name, err)
+		}
+
+		if event.Type != infrastructure.EventRound {
+			t.Fatalf("%s: expected %q after registration window, got %q", tc.name, infrastructure.EventRound, event.Type)
+		}
+
+		var round Round
+		if err := json.Unmarshal(event.Data, &round); err != nil {
+			t.Fatalf("%s: can not unmarshal round event: %v", tc.name, err)
+		}
+
+		if len(round.Competitors) != tc.registered {
+			t.Fatalf("%s: expected %d competitors, got %d", tc.name, tc.registered, len(round.Competitors))
+		}
+
+		if state.Overview().Phase != tc.phase {
+			t.Fatalf("%s: expected phase %q, got %q", tc.name, tc.phase, state.Overview().Phase)
+		}
+
+		if (tc.result == nil) != (round.Result == nil) || (tc.result != nil && (tc.result.Decision != round.Result.Decision || tc.result.Winner != round.Result.Winner)) {
+			t.Fatalf("%s: expected result %+v, got %+v", tc.name, tc.result, round.Result)
+		}
+	}
+}
+
+func TestStateIdempotentRegistration(t *testing.T) {
+	state := NewState(&app.ArbiterConfig{
+		Competitors: 2,
+	})
+
+	first, err := state.Register(&Registration{
+		Competitor: Competitor{ID: "test_1", Name: "Test1", Health: 3, Damage: 1},
+		Key:        "key_1",
+	})
+	if err != nil {
+		t.Fatalf("unexpected first registration err: %v", err)
+	}
+
+	retry, err := state.Register(&Registration{
+		Competitor: Competitor{ID: "test_1_retry", Name: "Test1", Health: 3, Damage: 1},
+		Key:        "key_1",
+	})
+	if err != nil {
+		t.Fatalf("unexpected retried registration err: %v", err)
+	}
+
+	if retry.ID != first.ID {
+		t.Fatalf("retried registration should return competitor %q, got %q", first.ID, retry.ID)
+	}
+
+	if overview := state.Overview(); overview.Phase != PhaseRegistration || len(overview.Competitors) != 1 {
+		t.Fatalf("retried registration should not add competitor, got: %+v", overview)
+	}
+
+	if _, err := state.Register(&Registration{
+		Competitor: Competitor{ID: "test_1_impostor", Name: "Test1", Health: 3, Damage: 1},
+	}); err == nil {
+		t.Fatalf("registration with taken name and without key should be rejected")
+	}
+
+	if _, err := state.Register(&Registration{
+		Competitor: Competitor{ID: "test_1_impostor", Name: "Test1", Health: 3, Damage: 1},
+		Key:        "key_impostor",
+	}); err == nil {
+		t.Fatalf("registration with taken name and another key should be rejected")
+	}
+
+	if _, err := state.Register(&Registration{
+		Competitor: Competitor{ID: "test_2", Name: "Test2", Health: 1, Damage: 1},
+		Key:        "key_2",
+	}); err != nil {
+		t.Fatalf("unexpected second registration err: %v", err)
+	}
+
+	rejoined, err := state.Register(&Registration{
+		Competitor: Competitor{ID: "test_2_restarted", Name: "Test2", Health: 1, Damage: 1},
+		Key:        "key_2",
+	})
+	if err != nil {
+		t.Fatalf("unexpected rejoin err: %v", err)
+	}
+
+	if rejoined.ID != "test_2" {
+		t.Fatalf("rejoined competitor should be keyed by key, got %q", rejoined.ID)
+	}
+
+	if _, err := state.Register(&Registration{
+		Competitor: Competitor{ID: "test_3", Name: "Test3", Health: 1, Damage: 1},
+	}); err != ErrAlreadyStarted {
+		t.Fatalf("expected already started error for new competitor, got: %v", err)
+	}
+
+	shot, _ := infrastructure.NewEvent(infrastructure.EventShot, &Shot{From: "test_1", To: "test_2"})
+	if err := state.Handle(shot); err != nil {
+		t.Fatalf("unexpected shot err: %v", err)
+	}
+
+	if _, err := state.Register(&Registration{
+		Competitor: Competitor{ID: "test_2_restarted", Name: "Test2", Health: 1, Damage: 1},
+		Key:        "key_2",
+	}); err != ErrEliminated {
+		t.Fatalf("expected eliminated error for dead competitor, got: %v", err)
+	}
+}
+
+func TestStateRegistrationValidation(t *testing.T) {
+	state := NewState(&app.ArbiterConfig{
+		Competitors:   3,
+		MaxHealth:     10,
+		MaxDamage:     3,
+		StatBudget:    13,
+		ExpectedNames: []string{"Test1", "Test2"},
+	})
+
+	if _, err := state.Register(&Registration{
+		Competitor: Competitor{ID: "test_1", Name: "Test1", Health: 10, Damage: 1},
+	}); err != nil {
+		t.Fatalf("unexpected valid registration err: %v", err)
+	}
+
+	for _, tc := range []struct {
+		competitor Competitor
+		violations []Violation
+	}{
+		{
+			competitor: Competitor{ID: "test_2", Name: "Test2", Health: -1, Damage: 0},
+			violations: []Violation{
+				{Field: "health", Reason: "must be at least 1"},
+				{Field: "damage", Reason: "must be at least 1"},
+			},
+		},
+		{
+			competitor: Competitor{ID: "test_2", Name: "Test2", Health: 11, Damage: 4},
+			violations: []Violation{
+				{Field: "health", Reason: "must be at most 10"},
+				{Field: "damage", Reason: "must be at most 3"},
+				{Field: "stats", Reason: "cost 23 points, budget is 13"},
+			},
+		},
+		{
+			competitor: Competitor{ID: "test_2", Name: "Stranger", Health: 1, Damage: 1},
+			violations: []Violation{
+				{Field: "name", Reason: "is not expected in this shootout"},
+			},
+		},
+		{
+			competitor: Competitor{ID: "test_2", Name: "Test1", Health: 1, Damage: 1},
+			violations: []Violation{
+				{Field: "name", Reason: "is already taken"},
+			},
+		},
+	} {
+		_, err := state.Register(&Registration{Competitor: tc.competitor})
+
+		validationErr, ok := err.(*ValidationError)
+		if !ok {
+			t.Fatalf("expected validation error for %+v, got: %v", tc.competitor, err)
+		}
+
+		if len(validationErr.Violations) != len(tc.violations) {
+			t.Fatalf("expected %d violations for %+v, got: %v", len(tc.violations), tc.competitor, err)
+		}
+
+		for i := range tc.violations {
+			if *validationErr.Violations[i] != tc.violations[i] {
+				t.Fatalf("expected violation %+v for %+v, got %+v", tc.violations[i], tc.competitor, validationErr.Violations[i])
+			}
+		}
+	}
+}
+
+func TestStateArmor(t *testing.T) {
+	state := NewState(&app.ArbiterConfig{
+		Competitors: 2,
+	})
+
+	for _, competitor := range []*Competitor{
+		{ID: "test_1", Name: "Test1", Health: 5, Damage: 3, Armor: 2},
+		{ID: "test_2", Name: "Test2", Health: 5, Damage: 1, Armor: 1},
+	} {
+		if _, err := state.Register(&Registration{Competitor: *competitor}); err != nil {
+			t.Fatalf("unexpected registration err: %v", err)
+		}
+	}
+
+	for _, shot := range []*Shot{
+		{From: "test_1", To: "test_2"},
+		{From: "test_2", To: "test_1"},
+	} {
+		event, _ := infrastructure.NewEvent(infrastructure.EventShot, shot)
+		if err := state.Handle(event); err != nil {
+			t.Fatalf("unexpected shot err: %v", err)
+		}
+	}
+
+	overview := state.Overview()
+	if overview.Competitors["test_2"].Health != 3 {
+		t.Fatalf("armor should reduce damage by its value, got health %d", overview.Competitors["test_2"].Health)
+	}
+
+	if overview.Competitors["test_1"].Health != 4 {
+		t.Fatalf("shot should inflict at least 1 damage, got health %d", overview.Competitors["test_1"].Health)
+	}
+}
+
+func TestStateProbabilisticCombat(t *testing.T) {
+	play := func(cfg *app.ArbiterConfig) []*Hit {
+		state := NewState(cfg)
+
+		for _, competitor := range []*Competitor{
+			{ID: "test_1", Name: "Test1", Health: 100, Damage: 2, Accuracy: 10},
+			{ID: "test_2", Name: "Test2", Health: 100, Damage: 2, Speed: 30},
+		} {
+			if _, err := state.Register(&Registration{Competitor: *competitor}); err != nil {
+				t.Fatalf("unexpected registration err: %v", err)
+			}
+		}
+
+		var feed []*Hit
+		for i := 0; i < 20; i++ {
+			shot, _ := infrastructure.NewEvent(infrastructure.EventShot, &Shot{From: "test_1", To: "test_2"})
+			if err := state.Handle(shot); err != nil {
+				t.Fatalf("unexpected shot err: %v", err)
+			}
+
+			event, err := state.Emit()
+			if err != nil {
+				t.Fatalf("unexpected emission err: %v", err)
+			}
+
+			var round Round
+			if err := json.Unmarshal(event.Data, &round); err != nil {
+				t.Fatalf("can not unmarshal round event: %v", err)
+			}
+
+			feed = append(feed, round.Feed...)
+		}
+
+		return feed
+	}
+
+	cfg := &app.ArbiterConfig{
+		Competitors:         2,
+		ProbabilisticCombat: true,
+		Seed:                42,
+		CritChance:          50,
+	}
+
+	first, second := play(cfg), play(cfg)
+
+	outcomes := make(map[Outcome]int)
+	for i := range first {
+		if *first[i] != *second[i] {
+			t.Fatalf("games with the same seed should resolve shots the same way, got %+v and %+v", first[i], second[i])
+		}
+
+		outcomes[first[i].Outcome]++
+
+		switch first[i].Outcome {
+		case OutcomeMiss:
+			if first[i].Damage != 0 {
+				t.Fatalf("missed shot should not inflict damage, got %d", first[i].Damage)
+			}
+		case OutcomeCritical:
+			if first[i].Damage != 4 {
+				t.Fatalf("critical shot should inflict double damage, got %d", first[i].Damage)
+			}
+		case OutcomeHit:
+			if first[i].Damage != 2 {
+				t.Fatalf("shot should inflict shooter's damage, got %d", first[i].Damage)
+			}
+		}
+	}
+
+	if outcomes[OutcomeMiss] == 0 || outcomes[OutcomeCritical] == 0 || outcomes[OutcomeHit] == 0 {
+		t.Fatalf("expected all outcomes in 20 shots with 55%% hit and 50%% critical chance, got %v", outcomes)
+	}
+
+	for _, setting := range []string{"many", "101"} {
+		if err := DecodeConfig(&app.ArbiterConfig{BaseHitChanceSetting: setting}); err == nil {
+			t.Fatalf("base hit chance %q should be rejected", setting)
+		}
+	}
+
+	cfg.BaseHitChanceSetting = "0"
+	if err := DecodeConfig(cfg); err != nil || cfg.BaseHitChance == nil || *cfg.BaseHitChance != 0 {
+		t.Fatalf("zero base hit chance should be kept, got %v, err %v", cfg.BaseHitChance, err)
+	}
+
+	cfg.BaseHitChanceSetting = "20"
+	if err := DecodeConfig(cfg); err != nil {
+		t.Fatalf("unexpected decode err: %v", err)
+	}
+
+	for _, hit := range play(cfg) {
+		if hit.Outcome != OutcomeMiss {
+			t.Fatalf("speed higher than hit chance should dodge every shot, got %+v", hit)
+		}
+	}
+}
+
+func TestStateConfigFromEnv(t *testing.T) {
+	t.Setenv("WEAPONS", `{"knife": {"damage": 1}}`)
+	t.Setenv("ITEMS", `{"vest": {"kind": "shield", "rounds": 2}}`)
+	t.Setenv("ARENA", `{"width": 5, "height": 5}`)
+	t.Setenv("BASE_HIT_CHANCE", "0")
+
+	var cfg app.ArbiterConfig
+	if err := config.FromEnv().To(&cfg); err != nil {
+		t.Fatalf("unexpected config err: %v", err)
+	}
+
+	if err := DecodeConfig(&cfg); err != nil {
+		t.Fatalf("unexpected decode err: %v", err)
+	}
+
+	if cfg.Armory["knife"] == nil || cfg.ItemEffects["vest"] == nil || cfg.Battlefield == nil || cfg.BaseHitChance == nil {
+		t.Fatalf("catalogs should be decoded, got %+v", cfg)
+	}
+}
+
+func TestStateWeapons(t *testing.T) {
+	state := NewState(&app.ArbiterConfig{
+		Competitors: 2,
+		Armory: map[string]*app.Weapon{
+			"revolver": {Magazine: 2, Reload: 1, MinDamage: 1, MaxDamage: 1, Ammo: 1},
+		},
+	})
+
+	if _, err := state.Register(&Registration{Competitor: Competitor{ID: "test_0", Name: "Test0", Health: 5, Damage: 1}}); err == nil {
+		t.Fatalf("registration without weapon should be rejected")
+	}
+
+	for _, competitor := range []*Competitor{
+		{ID: "test_1", Name: "Test1", Health: 10, Damage: 1, Weapon: "revolver"},
+		{ID: "test_2", Name: "Test2", Health: 10, Damage: 1, Weapon: "revolver"},
+	} {
+		if _, err := state.Register(&Registration{Competitor: *competitor}); err != nil {
+			t.Fatalf("unexpected registration err: %v", err)
+		}
+	}
+
+	shoot := func() error {
+		event, _ := infrastructure.NewEvent(infrastructure.EventShot, &Shot{From: "test_1", To: "test_2"})
+		return state.Handle(event)
+	}
+
+	next := func() {
+		if _, err := state.Emit(); err != nil {
+			t.Fatalf("unexpected emission err: %v", err)
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := shoot(); err != nil {
+			t.Fatalf("unexpected shot err: %v", err)
+		}
+
+		next()
+	}
+
+	if err := shoot(); err != ErrReloading {
+		t.Fatalf("expected err %v, got %v", ErrReloading, err)
+	}
+
+	if health := state.Overview().Competitors["test_2"].Health; health != 6 {
+		t.Fatalf("shot should inflict shooter's and weapon's damage, got health %d", health)
+	}
+
+	next()
+
+	shooter := state.Overview().Competitors["test_1"]
+	if shooter.Reloading != 0 || shooter.Magazine != 1 || shooter.Ammo != 0 {
+		t.Fatalf("magazine should be loaded with remaining ammo, got %+v", shooter)
+	}
+
+	if err := shoot(); err != nil {
+		t.Fatalf("unexpected shot err: %v", err)
+	}
+
+	next()
+
+	if err := shoot(); err != ErrOutOfAmmo {
+		t.Fatalf("expected err %v, got %v", ErrOutOfAmmo, err)
+	}
+}
+
+func TestStateReload(t *testing.T) {
+	for _, reload := range []int{1, 3} {
+		state := NewState(&app.ArbiterConfig{
+			Competitors: 2,
+			Armory: map[string]*app.Weapon{
+				"pistol": {Magazine: 1, Reload: reload, Ammo: 5},
+			},
+		})
+
+		for _, competitor := range []*Competitor{
+			{ID: "test_1", Name: "Test1", Health: 100, Damage: 1, Weapon: "pistol"},
+			{ID: "test_2", Name: "Test2", Health: 100, Damage: 1, Weapon: "pistol"},
+		} {
+			if _, err := state.Register(&Registration{Competitor: *competitor}); err != nil {
+				t.Fatalf("unexpected registration err: %v", err)
+			}
+		}
+
+		shoot := func() error {
+			event, _ := infrastructure.NewEvent(infrastructure.EventShot, &Shot{From: "test_1", To: "test_2"})
+			return state.Handle(event)
+		}
+
+		if err := shoot(); err != nil {
+			t.Fatalf("unexpected shot err: %v", err)
+		}
+
+		blocked := 0
+		for i := 0; i < reload+2; i++ {
+			if _, err := state.Emit(); err != nil {
+				t.Fatalf("unexpected emission err: %v", err)
+			}
+
+			err := shoot()
+			if err == nil {
+				break
+			}
+
+			if err != ErrReloading {
+				t.Fatalf("expected err %v, got %v", ErrReloading, err)
+			}
+
+			blocked++
+		}
+
+		if blocked != reload {
+			t.Fatalf("reload of %d rounds should block %d rounds, blocked %d", reload, reload, blocked)
+		}
+	}
+}
+
+func TestStateTeams(t *testing.T) {
+	play := func(cfg *app.ArbiterConfig) (*State, error) {
+		state := NewState(cfg)
+
+		for _, competitor := range []*Competitor{
+			{ID: "test_1", Name: "Test1", Health: 4, Damage: 2, Team: "red"},
+			{ID: "test_2", Name: "Test2", Health: 4, Damage: 2, Team: "red"},
+			{ID: "test_3", Name: "Test3", Health: 2, Damage: 2, Team: "blue"},
+		} {
+			if _, err := state.Register(&Registration{Competitor: *competitor}); err != nil {
+				t.Fatalf("unexpected registration err: %v", err)
+			}
+		}
+
+		event, _ := infrastructure.NewEvent(infrastructure.EventShot, &Shot{From: "test_1", To: "test_2"})
+
+		return state, state.Handle(event)
+	}
+
+	state, err := play(&app.ArbiterConfig{Competitors: 3})
+	if err != ErrFriendlyFire {
+		t.Fatalf("expected err %v, got %v", ErrFriendlyFire, err)
+	}
+
+	event, _ := infrastructure.NewEvent(infrastructure.EventShot, &Shot{From: "test_1", To: "test_3"})
+	if err := state.Handle(event); err != nil {
+		t.Fatalf("unexpected shot err: %v", err)
+	}
+
+	event, err = state.Emit()
+	if err != nil {
+		t.Fatalf("unexpected emission err: %v", err)
+	}
+
+	var round Round
+	if err := json.Unmarshal(event.Data, &round); err != nil {
+		t.Fatalf("can not unmarshal round event: %v", err)
+	}
+
+	if round.Result == nil || round.Result.Team != "red" || round.Result.Winner != "" {
+		t.Fatalf("game should be won by the last team standing, got %+v", round.Result)
+	}
+
+	if stats := round.Result.Stats["test_1"]; stats.Shots != 1 || stats.Dealt != 2 || stats.Kills != 1 || !stats.Alive {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+
+	if stats := round.Result.Stats["test_3"]; stats.Taken != 2 || stats.Alive {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+
+	state, err = play(&app.ArbiterConfig{Competitors: 3, FriendlyFire: true, FriendlyFirePenalty: 50})
+	if err != nil {
+		t.Fatalf("unexpected shot err: %v", err)
+	}
+
+	overview := state.Overview()
+	if overview.Competitors["test_2"].Health != 2 || overview.Competitors["test_1"].Health != 3 {
+		t.Fatalf("friendly fire should damage teammate and punish shooter, got %+v and %+v",
+			overview.Competitors["test_1"], overview.Competitors["test_2"])
+	}
+
+	state, err = play(&app.ArbiterConfig{Competitors: 3, FriendlyFire: true, FriendlyFirePenalty: 200})
+	if err != nil {
+		t.Fatalf("unexpected shot err: %v", err)
+	}
+
+	event, err = state.Emit()
+	if err != nil {
+		t.Fatalf("unexpected emission err: %v", err)
+	}
+
+	if err := json.Unmarshal(event.Data, &round); err != nil {
+		t.Fatalf("can not unmarshal round event: %v", err)
+	}
+
+	if len(round.Feed) != 2 || *round.Feed[1] != (Hit{From: "test_1", To: "test_1", Outcome: OutcomePenalty, Damage: 4, Killed: true}) {
+		t.Fatalf("fatal penalty should be in the feed, got %+v", round.Feed)
+	}
+
+	if stats := state.Stats()["test_1"]; stats.Kills != 0 || stats.Alive {
+		t.Fatalf("fatal penalty should not count as a kill, got %+v", stats)
+	}
+}
+
+func TestStateActions(t *testing.T) {
+	state := NewState(&app.ArbiterConfig{
+		Competitors: 2,
+	})
+
+	for _, competitor := range []*Competitor{
+		{ID: "test_1", Name: "Test1", Health: 10, Damage: 2},
+		{ID: "test_2", Name: "Test2", Health: 10, Damage: 4},
+	} {
+		if _, err := state.Register(&Registration{Competitor: *competitor}); err != nil {
+			t.Fatalf("unexpected registration err: %v", err)
+		}
+	}
+
+	act := func(action *Action) error {
+		event, _ := infrastructure.NewEvent(infrastructure.EventAction, action)
+		return state.Handle(event)
+	}
+
+	round := func(actions ...*Action) {
+		for _, action := range actions {
+			if err := act(action); err != nil {
+				t.Fatalf("unexpected %s err: %v", action.Kind, err)
+			}
+		}
+
+		if _, err := state.Emit(); err != nil {
+			t.Fatalf("unexpected emission err: %v", err)
+		}
+	}
+
+	if _, err := state.Emit(); err != nil {
+		t.Fatalf("unexpected emission err: %v", err)
+	}
+
+	if err := act(&Action{Kind: "dance", From: "test_1"}); err != ErrUnacceptablePayload {
+		t.Fatalf("expected err %v, got %v", ErrUnacceptablePayload, err)
+	}
+
+	round(
+		&Action{Kind: ActionAim, From: "test_1"},
+		&Action{Kind: ActionCover, From: "test_2"},
+	)
+
+	if err := act(&Action{Kind: ActionShoot, From: "test_1", To: "test_2"}); err != nil {
+		t.Fatalf("unexpected shoot err: %v", err)
+	}
+
+	if err := act(&Action{Kind: ActionHeal, From: "test_1"}); err != ErrAlreadyActed {
+		t.Fatalf("expected err %v, got %v", ErrAlreadyActed, err)
+	}
+
+	round(&Action{Kind: ActionShoot, From: "test_2", To: "test_1"})
+
+	overview := state.Overview()
+	if overview.Competitors["test_2"].Health != 9 {
+		t.Fatalf("aimed shot at competitor in cover should inflict 1 damage, got health %d", overview.Competitors["test_2"].Health)
+	}
+
+	if overview.Competitors["test_1"].Health != 6 || overview.Competitors["test_1"].Aiming {
+		t.Fatalf("unexpected competitor after aimed shot %+v", overview.Competitors["test_1"])
+	}
+
+	round(&Action{Kind: ActionHeal, From: "test_1"}, &Action{Kind: ActionHeal, From: "test_2"})
+
+	overview = state.Overview()
+	if overview.Competitors["test_1"].Health != 8 || overview.Competitors["test_2"].Health != 10 {
+		t.Fatalf("heal should restore health up to initial, got %d and %d",
+			overview.Competitors["test_1"].Health, overview.Competitors["test_2"].Health)
+	}
+}
+
+func TestStateArena(t *testing.T) {
+	state := NewState(&app.ArbiterConfig{
+		Competitors: 3,
+		Battlefield: &app.Arena{
+			Width:     5,
+			Height:    5,
+			Range:     3,
+			Falloff:   50,
+			Obstacles: []app.Position{{X: 2, Y: 1}},
+			Spawns:    []app.Position{{X: 0, Y: 0}, {X: 0, Y: 2}, {X: 4, Y: 2}},
+		},
+	})
+
+	for _, competitor := range []*Competitor{
+		{ID: "test_1", Name: "Test1", Health: 10, Damage: 4},
+		{ID: "test_2", Name: "Test2", Health: 10, Damage: 4},
+		{ID: "test_3", Name: "Test3", Health: 10, Damage: 4},
+	} {
+		if _, err := state.Register(&Registration{Competitor: *competitor}); err != nil {
+			t.Fatalf("unexpected registration err: %v", err)
+		}
+	}
+
+	act := func(action *Action) error {
+		event, _ := infrastructure.NewEvent(infrastructure.EventAction, action)
+		return state.Handle(event)
+	}
+
+	next := func() *Round {
+		event, err := state.Emit()
+		if err != nil {
+			t.Fatalf("unexpected emission err: %v", err)
+		}
+
+		var round Round
+		if err := json.Unmarshal(event.Data, &round); err != nil {
+			t.Fatalf("can not unmarshal round event: %v", err)
+		}
+
+		return &round
+	}
+
+	round := next()
+	if round.Arena == nil || *round.Competitors["test_3"].Position != (Position{X: 4, Y: 2}) {
+		t.Fatalf("competitors should spawn at arena's spawns, got %+v", round.Competitors["test_3"])
+	}
+
+	for _, tc := range []struct {
+		action *Action
+		err    error
+	}{
+		{&Action{Kind: ActionShoot, From: "test_1", To: "test_3"}, ErrOutOfRange},
+		{&Action{Kind: ActionShoot, From: "test_2", To: "test_3"}, ErrOutOfRange},
+		{&Action{Kind: ActionMove, From: "test_1", Position: &Position{X: 2, Y: 2}}, ErrInvalidMove},
+		{&Action{Kind: ActionMove, From: "test_1", Position: &Position{X: 0, Y: 2}}, ErrInvalidMove},
+		{&Action{Kind: ActionMove, From: "test_1", Position: &Position{X: 1, Y: 1}}, nil},
+		{&Action{Kind: ActionMove, From: "test_3", Position: &Position{X: 3, Y: 1}}, nil},
+		{&Action{Kind: ActionShoot, From: "test_2", To: "test_1"}, nil},
+	} {
+		if err := act(tc.action); err != tc.err {
+			t.Fatalf("%s of %s: expected err %v, got %v", tc.action.Kind, tc.action.From, tc.err, err)
+		}
+	}
+
+	round = next()
+	if health := round.Competitors["test_1"].Health; health != 7 {
+		t.Fatalf("damage should fall off with distance, got health %d", health)
+	}
+
+	if err := act(&Action{Kind: ActionShoot, From: "test_1", To: "test_3"}); err != ErrNoLineOfSight {
+		t.Fatalf("expected err %v, got %v", ErrNoLineOfSight, err)
+	}
+}
+
+func TestStateEffects(t *testing.T) {
+	state := NewState(&app.ArbiterConfig{
+		Competitors: 2,
+		Armory: map[string]*app.Weapon{
+			"knife":  {Magazine: 10, Effect: &app.Effect{Kind: "bleeding", Rounds: 2, Amount: 1}},
+			"hammer": {Magazine: 10, Effect: &app.Effect{Kind: "stunned", Rounds: 1}},
+		},
+		ItemEffects: map[string]*app.Effect{
+			"vest": {Kind: "shield", Rounds: 5, Amount: 3},
+		},
+	})
+
+	if _, err := state.Register(&Registration{Competitor: Competitor{ID: "test_0", Name: "Test0", Health: 5, Damage: 1, Weapon: "knife", Items: []string{"bomb"}}}); err == nil {
+		t.Fatalf("registration with unknown item should be rejected")
+	}
+
+	for _, competitor := range []*Competitor{
+		{ID: "test_1", Name: "Test1", Health: 10, Damage: 1, Weapon: "knife"},
+		{ID: "test_2", Name: "Test2", Health: 10, Damage: 2, Weapon: "hammer", Items: []string{"vest"}},
+	} {
+		if _, err := state.Register(&Registration{Competitor: *competitor}); err != nil {
+			t.Fatalf("unexpected registration err: %v", err)
+		}
+	}
+
+	act := func(action *Action) error {
+		event, _ := infrastructure.NewEvent(infrastructure.EventAction, action)
+		return state.Handle(event)
+	}
+
+	next := func() map[string]*Competitor {
+		if _, err := state.Emit(); err != nil {
+			t.Fatalf("unexpected emission err: %v", err)
+		}
+
+		return state.Overview().Competitors
+	}
+
+	next()
+
+	for _, action := range []*Action{
+		{Kind: ActionShoot, From: "test_1", To: "test_2"},
+		{Kind: ActionShoot, From: "test_2", To: "test_1"},
+	} {
+		if err := act(action); err != nil {
+			t.Fatalf("unexpected shoot err: %v", err)
+		}
+	}
+
+	// effects applied last round are not ticked yet
+	competitors := next()
+	if competitors["test_2"].Health != 9 || len(competitors["test_2"].Effects) != 1 || competitors["test_2"].Effects[0].Rounds != 2 {
+		t.Fatalf("shot should make target bleed, got %+v", competitors["test_2"])
+	}
+
+	if err := act(&Action{Kind: ActionHeal, From: "test_1"}); err != ErrStunned {
+		t.Fatalf("expected err %v, got %v", ErrStunned, err)
+	}
+
+	if err := act(&Action{Kind: ActionUse, From: "test_2", Item: "vest"}); err != nil {
+		t.Fatalf("unexpected use err: %v", err)
+	}
+
+	competitors = next()
+	if competitors["test_2"].Health != 8 || len(competitors["test_2"].Items) != 0 {
+		t.Fatalf("bleeding should inflict damage every round, got %+v", competitors["test_2"])
+	}
+
+	if err := act(&Action{Kind: ActionShoot, From: "test_1", To: "test_2"}); err != nil {
+		t.Fatalf("stun should wear off, got %v", err)
+	}
+
+	competitors = next()
+	if competitors["test_2"].Health != 8 {
+		t.Fatalf("shield should absorb the shot, got health %d", competitors["test_2"].Health)
+	}
+
+	for _, effect := range competitors["test_2"].Effects {
+		if effect.Kind == EffectShield && effect.Amount != 2 {
+			t.Fatalf("shield should absorb damage, got %+v", effect)
+		}
+
+		if effect.Kind == EffectBleeding && effect.Rounds != 2 {
+			t.Fatalf("another hit should renew bleeding, got %+v", effect)
+		}
+	}
+}
+
+func TestStateBleedingOut(t *testing.T) {
+	state := NewState(&app.ArbiterConfig{
+		Competitors: 2,
+		Armory: map[string]*app.Weapon{
+			"knife": {Magazine: 10, Effect: &app.Effect{Kind: "bleeding", Rounds: 2, Amount: 5}},
+		},
+	})
+
+	for _, competitor := range []*Competitor{
+		{ID: "test_1", Name: "Test1", Health: 10, Damage: 1, Weapon: "knife"},
+		{ID: "test_2", Name: "Test2", Health: 3, Damage: 1, Weapon: "knife"},
+	} {
+		if _, err := state.Register(&Registration{Competitor: *competitor}); err != nil {
+			t.Fatalf("unexpected registration err: %v", err)
+		}
+	}
+
+	event, _ := infrastructure.NewEvent(infrastructure.EventAction, &Action{Kind: ActionShoot, From: "test_1", To: "test_2"})
+	if err := state.Handle(event); err != nil {
+		t.Fatalf("unexpected shot err: %v", err)
+	}
+
+	var round Round
+	for i := 0; i < 2; i++ {
+		event, err := state.Emit()
+		if err != nil {
+			t.Fatalf("unexpected emission err: %v", err)
+		}
+
+		if err := json.Unmarshal(event.Data, &round); err != nil {
+			t.Fatalf("can not unmarshal round event: %v", err)
+		}
+	}
+
+	if len(round.Feed) != 1 {
+		t.Fatalf("bleeding out should be in the feed, got %+v", round.Feed)
+	}
+
+	if hit := *round.Feed[0]; hit != (Hit{From: "test_1", To: "test_2", Outcome: OutcomeBleeding, Damage: 5, Killed: true}) {
+		t.Fatalf("bleeding out should be a kill of whoever caused it, got %+v", hit)
+	}
+
+	if round.Result == nil || round.Result.Winner != "test_1" {
+		t.Fatalf("expected test_1 to win, got %+v", round.Result)
+	}
+
+	if stats := round.Result.Stats["test_1"]; stats.Kills != 1 || stats.Dealt != 6 {
+		t.Fatalf("bleeding should be credited to whoever caused it, got %+v", stats)
+	}
+}
+
+func TestStateSuddenDeath(t *testing.T) {
+	play := func(rule SuddenDeath, shoot bool, health ...int) (*State, *Result) {
+		state := NewState(&app.ArbiterConfig{
+			Competitors: 2,
+			MaxRounds:   2,
+			SuddenDeath: string(rule),
+		})
+
+		for i, h := range health {
+			competitor := Competitor{ID: fmt.Sprintf("test_%d", i), Name: fmt.Sprintf("Test%d", i), Health: h, Damage: 1}
+			if _, err := state.Register(&Registration{Competitor: competitor}); err != nil {
+				t.Fatalf("unexpected registration err: %v", err)
+			}
+		}
+
+		for i := 0; i < 10; i++ {
+			event, err := state.Emit()
+			if err != nil {
+				t.Fatalf("%s: unexpected emission err: %v", rule, err)
+			}
+
+			var round Round
+			if err := json.Unmarshal(event.Data, &round); err != nil {
+				t.Fatalf("can not unmarshal round event: %v", err)
+			}
+
+			if round.Result != nil {
+				return state, round.Result
+			}
+
+			if state.Stalled() {
+				t.Fatalf("%s: game with round limit should not stall", rule)
+			}
+
+			if shoot && i == 2 {
+				shot, _ := infrastructure.NewEvent(infrastructure.EventShot, &Shot{From: "test_0", To: "test_1"})
+				if err := state.Handle(shot); err != nil {
+					t.Fatalf("unexpected shot err: %v", err)
+				}
+			}
+		}
+
+		t.Fatalf("%s: game should be over in 10 rounds", rule)
+
+		return nil, nil
+	}
+
+	state, result := play(SuddenDeathHealth, false, 10, 20)
+	if result.Decision != DecisionSuddenDeath || result.SuddenDeath != SuddenDeathHealth || result.Winner != "" {
+		t.Fatalf("competitors with the same health percentage should draw, got %+v", result)
+	}
+
+	if round := state.Overview().Round; round != 3 {
+		t.Fatalf("game should be decided right after round limit, got round %d", round)
+	}
+
+	_, result = play(SuddenDeathShrink, false, 3, 5)
+	if result.Decision != DecisionSuddenDeath || result.SuddenDeath != SuddenDeathShrink || result.Winner != "test_1" {
+		t.Fatalf("shrinking health should leave the healthiest competitor, got %+v", result)
+	}
+
+	_, result = play(SuddenDeathEscalation, true, 5, 2)
+	if result.Decision != DecisionSuddenDeath || result.Winner != "test_0" || result.Stats["test_0"].Dealt != 2 {
+		t.Fatalf("shot in the first sudden death round should inflict double damage, got %+v", result)
+	}
+
+	_, result = play(SuddenDeathEscalation, false, 3, 5)
+	if result.Decision != DecisionSuddenDeath || result.Winner != "test_1" {
+		t.Fatalf("escalation should end the game even when nobody shoots, got %+v", result)
+	}
+}
+
+func TestStateStalled(t *testing.T) {
+	state := NewState(&app.ArbiterConfig{
+		Competitors: 2,
+	})
+
+	for _, competitor := range []*Competitor{
+		{ID: "test_1", Name: "Test1", Health: 10, Damage: 1},
+		{ID: "test_2", Name: "Test2", Health: 10, Damage: 1},
+	} {
+		if _, err := state.Register(&Registration{Competitor: *competitor}); err != nil {
+			t.Fatalf("unexpected registration err: %v", err)
+		}
+	}
+
+	for round := 0; round < stallRounds; round++ {
+		if _, err := state.Emit(); err != nil {
+			t.Fatalf("unexpected emission err: %v", err)
+		}
+
+		if state.Stalled() {
+			t.Fatalf("round %d: game should not stall before %d idle rounds", round, stallRounds)
+		}
+	}
+
+	// identical rounds do not stall the game as long as somebody acts
+	event, _ := infrastructure.NewEvent(infrastructure.EventAction, &Action{Kind: ActionCover, From: "test_1"})
+	if err := state.Handle(event); err != nil {
+		t.Fatalf("unexpected cover err: %v", err)
+	}
+
+	if _, err := state.Emit(); err != nil {
+		t.Fatalf("unexpected emission err: %v", err)
+	}
+
+	if state.Stalled() {
+		t.Fatalf("game should not stall after competitor acted")
+	}
+
+	for round := 0; round < stallRounds; round++ {
+		if _, err := state.Emit(); err != nil {
+			t.Fatalf("unexpected emission err: %v", err)
+		}
+	}
+
+	if !state.Stalled() {
+		t.Fatalf("game should stall after %d idle rounds", stallRounds)
+	}
+}
+
+func TestStateReady(t *testing.T) {
+	state := NewState(&app.ArbiterConfig{
+		Competitors: 2,
+		TurnBased:   true,
+	})
+
+	for _, competitor := range []*Competitor{
+		{ID: "test_1", Name: "Test1", Health: 10, Damage: 1},
+		{ID: "test_2", Name: "Test2", Health: 10, Damage: 1},
+	} {
+		if _, err := state.Register(&Registration{Competitor: *competitor}); err != nil {
+			t.Fatalf("unexpected registration err: %v", err)
+		}
+	}
+
+	for round := 0; round < 2; round++ {
+		if state.Ready() {
+			t.Fatalf("round %d: state should not be ready before competitors act", round)
+		}
+
+		for _, id := range []string{"test_1", "test_2"} {
+			event, _ := infrastructure.NewEvent(infrastructure.EventAction, &Action{Kind: ActionCover, From: id})
+			if err := state.Handle(event); err != nil {
+				t.Fatalf("unexpected cover err: %v", err)
+			}
+		}
+
+		if !state.Ready() {
+			t.Fatalf("round %d: state should be ready when every competitor acted", round)
+		}
+
+		if _, err := state.Emit(); err != nil {
+			t.Fatalf("unexpected emission err: %v", err)
+		}
+	}
+
+	event, _ := infrastructure.NewEvent(infrastructure.EventAction, &Action{Kind: ActionUse, From: "test_1", Item: "grenade"})
+	if err := state.Handle(event); err != ErrNoItem {
+		t.Fatalf("expected err %v, got %v", ErrNoItem, err)
+	}
+
+	if err := state.Handle(event); err != ErrAlreadyActed {
+		t.Fatalf("rejected action should take competitor's turn, got err %v", err)
+	}
+
+	event, _ = infrastructure.NewEvent(infrastructure.EventAction, &Action{Kind: ActionCover, From: "test_2"})
+	if err := state.Handle(event); err != nil {
+		t.Fatalf("unexpected cover err: %v", err)
+	}
+
+	if !state.Ready() {
+		t.Fatalf("state should be ready when competitor's action was rejected")
+	}
+}
+
+// truceRules forbid shooting and end the game after the second round.
+type truceRules struct {
+	StandardRules
+}
+
+func (r truceRules) Apply(s *State, competitor *Competitor, action *Action) error {
+	if action.Kind == ActionShoot {
+		return ErrUnacceptablePayload
+	}
+
+	return r.StandardRules.Apply(s, competitor, action)
+}
+
+func (truceRules) Victory(s *State) *Result {
+	if s.Round() < 2 {
+		return nil
+	}
+
+	return &Result{Decision: "truce"}
+}
+
+func TestStateRules(t *testing.T) {
+	if _, err := Ruleset("truce"); !errors.Is(err, ErrUnknownRules) {
+		t.Fatalf("expected err %v, got %v", ErrUnknownRules, err)
+	}
+
+	RegisterRules("truce", func(*app.ArbiterConfig) Rules { return truceRules{} })
+	t.Cleanup(func() {
+		rulesetsLock.Lock()
+		defer rulesetsLock.Unlock()
+
+		delete(rulesets, "truce")
+	})
+
+	state := NewState(&app.ArbiterConfig{
+		Competitors: 2,
+		Rules:       "truce",
+	})
+
+	for _, competitor := range []*Competitor{
+		{ID: "test_1", Name: "Test1", Health: 10, Damage: 1},
+		{ID: "test_2", Name: "Test2", Health: 10, Damage: 1},
+	} {
+		if _, err := state.Register(&Registration{Competitor: *competitor}); err != nil {
+			t.Fatalf("unexpected registration err: %v", err)
+		}
+	}
+
+	if _, err := state.Emit(); err != nil {
+		t.Fatalf("unexpected emission err: %v", err)
+	}
+
+	shot, _ := infrastructure.NewEvent(infrastructure.EventShot, &Shot{From: "test_1", To: "test_2"})
+	if err := state.Handle(shot); err != ErrUnacceptablePayload {
+		t.Fatalf("expected err %v, got %v", ErrUnacceptablePayload, err)
+	}
+
+	heal, _ := infrastructure.NewEvent(infrastructure.EventAction, &Action{Kind: ActionHeal, From: "test_1"})
+	if err := state.Handle(heal); err != nil {
+		t.Fatalf("unexpected heal err: %v", err)
+	}
+
+	if _, err := state.Emit(); err != nil {
+		t.Fatalf("unexpected emission err: %v", err)
+	}
+
+	if overview := state.Overview(); overview.Result == nil || overview.Result.Decision != "truce" || overview.Result.Stats == nil {
+		t.Fatalf("ruleset should decide the game, got %+v", overview.Result)
+	}
+}
+
+func TestStateRulesScript(t *testing.T) {
+	for _, script := range []string{"on_shot = 1", "def on_shot(:", `load("os.star", "exec")`} {
+		if _, err := NewScriptRules(&app.ArbiterConfig{RulesScript: script}, StandardRules{}); !errors.Is(err, ErrInvalidScript) {
+			t.Fatalf("expected err %v for %q, got %v", ErrInvalidScript, script, err)
+		}
+	}
+
+	state := NewState(&app.ArbiterConfig{
+		Competitors: 2,
+		RulesScript: `
+def on_shot(game, shot):
+    return shot["damage"] * 2
+
+def on_round_end(game):
+    return {id: -1 for id in game["competitors"]}
+
+def is_finished(game):
+    if game["round"] < 3:
+        return None
+    return {"winner": "test_2"}
+`,
+	})
+
+	for _, competitor := range []*Competitor{
+		{ID: "test_1", Name: "Test1", Health: 10, Damage: 2},
+		{ID: "test_2", Name: "Test2", Health: 10, Damage: 1},
+	} {
+		if _, err := state.Register(&Registration{Competitor: *competitor}); err != nil {
+			t.Fatalf("unexpected registration err: %v", err)
+		}
+	}
+
+	if _, err := state.Emit(); err != nil {
+		t.Fatalf("unexpected emission err: %v", err)
+	}
+
+	shot, _ := infrastructure.NewEvent(infrastructure.EventShot, &Shot{From: "test_1", To: "test_2"})
+	if err := state.Handle(shot); err != nil {
+		t.Fatalf("unexpected shot err: %v", err)
+	}
+
+	// doubled damage of the shot and a point lost at the end of the round
+	event, err := state.Emit()
+	if err != nil {
+		t.Fatalf("unexpected emission err: %v", err)
+	}
+
+	if health := state.competitors["test_2"].Health; health != 4 {
+		t.Fatalf("expected health 4, got %d", health)
+	}
+
+	var round Round
+	if err := json.Unmarshal(event.Data, &round); err != nil {
+		t.Fatalf("can not unmarshal round event: %v", err)
+	}
+
+	scripted := 0
+	for _, hit := range round.Feed {
+		if hit.Outcome == OutcomeScripted && hit.From == "" && hit.Damage == 1 {
+			scripted++
+		}
+	}
+
+	if scripted != 2 {
+		t.Fatalf("health lost by the rules should be in the feed, got %+v", round.Feed)
+	}
+
+	if _, err := state.Emit(); err != nil {
+		t.Fatalf("unexpected emission err: %v", err)
+	}
+
+	if overview := state.Overview(); overview.Result == nil || overview.Result.Decision != DecisionScripted || overview.Result.Winner != "test_2" {
+		t.Fatalf("script should decide the game, got %+v", overview.Result)
+	}
+}
+
+func TestStateRulesScriptLimits(t *testing.T) {
+	state := NewState(&app.ArbiterConfig{
+		Competitors:      2,
+		RulesScriptSteps: 1000,
+		RulesScript: `
+def is_finished(game):
+    for i in range(1000000):
+        pass
+    return True
+`,
+	})
+
+	for _, competitor := range []*Competitor{
+		{ID: "test_1", Name: "Test1", Health: 10, Damage: 1},
+		{ID: "test_2", Name: "Test2", Health: 10, Damage: 1},
+	} {
+		if _, err := state.Register(&Registration{Competitor: *competitor}); err != nil {
+			t.Fatalf("unexpected registration err: %v", err)
+		}
+	}
+
+	if _, err := state.Emit(); err != nil {
+		t.Fatalf("unexpected emission err: %v", err)
+	}
+
+	// runaway hook is cancelled and default rules carry on
+	if overview := state.Overview(); overview.Result != nil {
+		t.Fatalf("expected game to go on, got %+v", overview.Result)
+	}
+}
+
+func TestStateRulesScriptTimeout(t *testing.T) {
+	script, err := NewScriptRules(&app.ArbiterConfig{
+		RulesScriptSteps:   1 << 40,
+		RulesScriptTimeout: 50 * time.Millisecond,
+		RulesScript: `
+def is_finished(game):
+    for i in range(1000000000):
+        pass
+    return True
+`,
+	}, StandardRules{})
+	if err != nil {
+		t.Fatalf("unexpected script err: %v", err)
+	}
+
+	// timeout is renewed for every call
+	for i := 0; i < 2; i++ {
+		started := time.Now()
+		if _, err := script.call(hookIsFinished, script.isFinished, game(NewState(&app.ArbiterConfig{}))); err == nil {
+			t.Fatalf("expected runaway hook to be cancelled")
+		}
+
+		if elapsed := time.Since(started); elapsed > time.Second {
+			t.Fatalf("runaway hook should be cancelled by timeout, took %v", elapsed)
+		}
+	}
+
+	if _, err := NewScriptRules(&app.ArbiterConfig{
+		RulesScriptTimeout: 50 * time.Millisecond,
+		RulesScript: `
+for i in range(1000000000):
+    pass
+`,
+	}, StandardRules{}); !errors.Is(err, ErrInvalidScript) {
+		t.Fatalf("expected err %v, got %v", ErrInvalidScript, err)
+	}
+}
+
+func TestStateRulesScriptSize(t *testing.T) {
+	source := "# " + strings.Repeat("x", maxScriptSize)
+	if _, err := NewScriptRules(&app.ArbiterConfig{RulesScript: source}, StandardRules{}); !errors.Is(err, ErrInvalidScript) {
+		t.Fatalf("expected err %v, got %v", ErrInvalidScript, err)
+	}
+
+	state := NewState(&app.ArbiterConfig{
+		Competitors: 2,
+		RulesScript: `
+def on_round_end(game):
+    changes = {id: -1 for id in game["competitors"]}
+    changes["extra"] = 0
+    return changes
+
+def is_finished(game):
+    return {"winner": "x" * 1000}
+`,
+	})
+
+	for _, competitor := range []*Competitor{
+		{ID: "test_1", Name: "Test1", Health: 10, Damage: 1},
+		{ID: "test_2", Name: "Test2", Health: 10, Damage: 1},
+	} {
+		if _, err := state.Register(&Registration{Competitor: *competitor}); err != nil {
+			t.Fatalf("unexpected registration err: %v", err)
+		}
+	}
+
+	if _, err := state.Emit(); err != nil {
+		t.Fatalf("unexpected emission err: %v", err)
+	}
+
+	overview := state.Overview()
+	if overview.Competitors["test_1"].Health != 10 {
+		t.Fatalf("oversized changes should be ignored, got %+v", overview.Competitors["test_1"])
+	}
+
+	if overview.Result == nil || overview.Result.Winner != "" {
+		t.Fatalf("oversized winner should be dropped, got %+v", overview.Result)
+	}
+}
+
+func TestStateFogOfWar(t *testing.T) {
+	state := NewState(&app.ArbiterConfig{Competitors: 3})
+
+	for _, competitor := range []*Competitor{
+		{ID: "test_1", Name: "Test1", Health: 9, Damage: 4, Armor: 1, Team: "red"},
+		{ID: "test_2", Name: "Test2", Health: 9, Damage: 2, Team: "red"},
+		{ID: "test_3", Name: "Test3", Health: 9, Damage: 1},
+	} {
+		if _, err := state.Register(&Registration{Competitor: *competitor}); err != nil {
+			t.Fatalf("unexpected registration err: %v", err)
+		}
+	}
+
+	if _, err := state.Emit(); err != nil {
+		t.Fatalf("unexpected emission err: %v", err)
+	}
+
+	shot, _ := infrastructure.NewEvent(infrastructure.EventShot, &Shot{From: "test_1", To: "test_3"})
+	if err := state.Handle(shot); err != nil {
+		t.Fatalf("unexpected shot err: %v", err)
+	}
+
+	event, err := state.Emit()
+	if err != nil {
+		t.Fatalf("unexpected emission err: %v", err)
+	}
+
+	views, err := state.Views(event)
+	if err != nil {
+		t.Fatalf("unexpected views err: %v", err)
+	}
+
+	var red, lone Round
+	if err := json.Unmarshal(views["test_2"].Data, &red); err != nil {
+		t.Fatalf("unexpected unmarshal err: %v", err)
+	}
+
+	if err := json.Unmarshal(views["test_3"].Data, &lone); err != nil {
+		t.Fatalf("unexpected unmarshal err: %v", err)
+	}
+
+	if teammate := red.Competitors["test_1"]; teammate.Health != 9 || teammate.Damage != 4 || teammate.Condition != "" {
+		t.Fatalf("teammate should be seen as is, got %+v", teammate)
+	}
+
+	if enemy := red.Competitors["test_3"]; enemy.Health != 0 || enemy.Damage != 0 || enemy.Condition != ConditionWounded {
+		t.Fatalf("enemy should be concealed, got %+v", enemy)
+	}
+
+	// enemy revealed damage by shooting, its hits are seen by the target
+	if enemy := lone.Competitors["test_1"]; enemy.Health != 0 || enemy.Damage != 4 || enemy.Condition != ConditionHealthy {
+		t.Fatalf("enemy that shot should reveal damage, got %+v", enemy)
+	}
+
+	if enemy := lone.Competitors["test_2"]; enemy.Damage != 0 {
+		t.Fatalf("enemy that did not shoot should conceal damage, got %+v", enemy)
+	}
+
+	if enemy := lone.Competitors["test_1"]; enemy.Armor != 0 || enemy.Team != "red" {
+		t.Fatalf("enemy should conceal stats but not team, got %+v", enemy)
+	}
+
+	if teammate := red.Competitors["test_1"]; teammate.Armor != 1 {
+		t.Fatalf("teammate should reveal stats, got %+v", teammate)
+	}
+
+	var raw struct {
+		Competitors map[string]map[string]interface{} `json:"Competitors"`
+	}
+	if err := json.Unmarshal(views["test_3"].Data, &raw); err != nil {
+		t.Fatalf("unexpected unmarshal err: %v", err)
+	}
+
+	if _, ok := raw.Competitors["test_1"]["health"]; ok {
+		t.Fatalf("health of concealed enemy should be omitted, got %+v", raw.Competitors["test_1"])
+	}
+
+	if len(lone.Feed) != 1 || lone.Feed[0].Damage != 4 || len(red.Feed) != 1 || red.Feed[0].Damage != 4 {
+		t.Fatalf("hits of own side should be seen, got %+v and %+v", lone.Feed, red.Feed)
+	}
+}
+
+func TestStateStats(t *testing.T) {
+	state := NewState(&app.ArbiterConfig{Competitors: 2})
+
+	for _, competitor := range []*Competitor{
+		{ID: "test_1", Name: "Test1", Health: 10, Damage: 10},
+		{ID: "test_2", Name: "Test2", Health: 10, Damage: 1},
+	} {
+		if _, err := state.Register(&Registration{Competitor: *competitor}); err != nil {
+			t.Fatalf("unexpected registration err: %v", err)
+		}
+	}
+
+	if _, err := state.Emit(); err != nil {
+		t.Fatalf("unexpected emission err: %v", err)
+	}
+
+	shot, _ := infrastructure.NewEvent(infrastructure.EventShot, &Shot{From: "test_1", To: "test_2"})
+	if err := state.Handle(shot); err != nil {
+		t.Fatalf("unexpected shot err: %v", err)
+	}
+
+	stats := state.Stats()
+	if shooter := stats["test_1"]; shooter.Shots != 1 || shooter.Dealt != 10 || shooter.Kills != 1 || !shooter.Alive {
+		t.Fatalf("unexpected shooter stats %+v", shooter)
+	}
+
+	if target := stats["test_2"]; target.Taken != 10 || target.Alive {
+		t.Fatalf("unexpected target stats %+v", target)
+	}
+
+	// stats are copied
+	stats["test_1"].Kills = 0
+	if state.Stats()["test_1"].Kills != 1 {
+		t.Fatalf("stats should not be shared")
+	}
+}
@@ -0,0 +1,122 @@
+package shootout
+
+import (
+	"log"
+
+	"github.com/damejeras/shootout/internal/app"
+)
+
+const (
+	// SuddenDeathEscalation multiplies damage of shots by the number of
+	// sudden death rounds plus one and shrinks health as well, so the game
+	// ends even when nobody shoots.
+	SuddenDeathEscalation SuddenDeath = "escalation"
+	// SuddenDeathShrink takes health of every competitor away, as much as
+	// there were sudden death rounds.
+	SuddenDeathShrink SuddenDeath = "shrink"
+	// SuddenDeathHealth ends the game right away, competitor with the highest
+	// percentage of remaining health wins.
+	SuddenDeathHealth SuddenDeath = "health"
+)
+
+// stallRounds without a single accepted action stall the game without round
+// limit, competitors are gone or can not fight each other.
+const stallRounds = 10
+
+type SuddenDeath string
+
+// suddenDeath guarantees termination of the game after the round limit.
+type suddenDeath struct {
+	maxRounds int
+	rule      SuddenDeath
+}
+
+func newSuddenDeath(cfg *app.ArbiterConfig) *suddenDeath {
+	sd := &suddenDeath{
+		maxRounds: cfg.MaxRounds,
+		rule:      SuddenDeath(cfg.SuddenDeath),
+	}
+
+	if sd.rule == "" {
+		sd.rule = SuddenDeathHealth
+	}
+
+	return sd
+}
+
+// Stalled reports whether nobody acted for stallRounds in a game without
+// round limit, games with round limit end with sudden death instead.
+func (s *State) Stalled() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.suddenDeath.maxRounds == 0 && s.idle > stallRounds
+}
+
+// rounds played in sudden death, zero when it has not kicked in.
+func (s *State) suddenDeathRounds() int {
+	if s.suddenDeath.maxRounds == 0 || s.round <= s.suddenDeath.maxRounds {
+		return 0
+	}
+
+	return s.round - s.suddenDeath.maxRounds
+}
+
+// escalate damage of the shot in sudden death.
+func (s *State) escalate(damage int) int {
+	if s.suddenDeath.rule != SuddenDeathEscalation {
+		return damage
+	}
+
+	return damage * (s.suddenDeathRounds() + 1)
+}
+
+// shrink takes health away from every competitor in sudden death.
+func (s *State) shrink() {
+	rounds := s.suddenDeathRounds()
+	if rounds == 1 {
+		log.Printf("☠️ Sudden death: %s ☠️", s.suddenDeath.rule)
+	}
+
+	if rounds == 0 || s.suddenDeath.rule == SuddenDeathHealth {
+		return
+	}
+
+	for _, competitor := range s.competitors {
+		if s.Inflict(competitor, "", OutcomeSuddenDeath, rounds) {
+			log.Printf("☠️ %s did not survive sudden death ☠️", competitor.Name)
+		}
+	}
+}
+
+// healthiest decides the game in favour of competitor with the highest
+// percentage of remaining health.
+func (s *State) healthiest() *Result {
+	var (
+		leader *Competitor
+		best   float64
+		tie    bool
+	)
+
+	for id, competitor := range s.competitors {
+		remaining := float64(competitor.Health) / float64(s.vitality[id])
+		switch {
+		case leader == nil || remaining > best:
+			leader, best, tie = competitor, remaining, false
+		case remaining == best:
+			tie = true
+		}
+	}
+
+	result := &Result{
+		Decision:    DecisionSuddenDeath,
+		SuddenDeath: s.suddenDeath.rule,
+	}
+
+	if leader != nil && !tie {
+		result.Winner = leader.ID
+		result.Team = leader.Team
+	}
+
+	return result
+}
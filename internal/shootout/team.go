@@ -0,0 +1,56 @@
+package shootout
+
+import (
+	"fmt"
+	"log"
+)
+
+var ErrFriendlyFire = fmt.Errorf("competitor shot at a teammate")
+
+// side competitor fights for, competitor without team is a side of their own.
+func side(competitor *Competitor) string {
+	if competitor.Team == "" {
+		return "#" + competitor.ID
+	}
+
+	return competitor.Team
+}
+
+func teammates(a, b *Competitor) bool {
+	return a.Team != "" && a.Team == b.Team
+}
+
+// Sides counts teams and lone competitors still in the game.
+func (s *State) Sides() int {
+	sides := make(map[string]struct{})
+	for _, competitor := range s.competitors {
+		sides[side(competitor)] = struct{}{}
+	}
+
+	return len(sides)
+}
+
+// punish competitor who shot a teammate, penalty is a percentage of damage
+// inflicted to the teammate.
+func (s *State) punish(shooter *Competitor, damage int) {
+	penalty := damage * s.penalty / 100
+	if penalty == 0 {
+		return
+	}
+
+	if s.Inflict(shooter, shooter.ID, OutcomePenalty, penalty) {
+		log.Printf("⚖️ %s paid for friendly fire with life ⚖️", shooter.Name)
+	}
+}
+
+func (s *State) record(hit *Hit) {
+	shooter, target := s.stats[hit.From], s.stats[hit.To]
+
+	shooter.Shots++
+	if hit.Outcome != OutcomeMiss {
+		shooter.Hits++
+	}
+
+	shooter.Dealt += hit.Damage
+	target.Taken += hit.Damage
+}
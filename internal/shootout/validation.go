@@ -0,0 +1,161 @@
+package shootout
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/damejeras/shootout/internal/app"
+)
+
+const (
+	defaultMinHealth = 1
+	maxAccuracy      = 100
+)
+
+// Price of a single stat point, paid from stat budget.
+const (
+	healthCost   = 1
+	damageCost   = 3
+	accuracyCost = 1
+	armorCost    = 4
+	speedCost    = 1
+)
+
+// Violation describes single reason why registration was rejected.
+type Violation struct {
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
+
+type ValidationError struct {
+	Violations []*Violation
+}
+
+func (e *ValidationError) Error() string {
+	reasons := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		reasons[i] = violation.Field + " " + violation.Reason
+	}
+
+	return "invalid registration: " + strings.Join(reasons, ", ")
+}
+
+// Limits bound competitor stats. Zero value means the stat is not limited.
+type Limits struct {
+	MinHealth     int
+	MaxHealth     int
+	MaxDamage     int
+	StatBudget    int
+	ExpectedNames []string
+	Weapons       map[string]*app.Weapon
+	Items         map[string]*app.Effect
+}
+
+func newLimits(cfg *app.ArbiterConfig) *Limits {
+	limits := &Limits{
+		MinHealth:     cfg.MinHealth,
+		MaxHealth:     cfg.MaxHealth,
+		MaxDamage:     cfg.MaxDamage,
+		StatBudget:    cfg.StatBudget,
+		ExpectedNames: cfg.ExpectedNames,
+		Weapons:       cfg.Armory,
+		Items:         cfg.ItemEffects,
+	}
+
+	if limits.MinHealth < defaultMinHealth {
+		limits.MinHealth = defaultMinHealth
+	}
+
+	return limits
+}
+
+// validate checks competitor against limits.
+func (l *Limits) validate(competitor *Competitor) error {
+	var violations []*Violation
+	violate := func(field, reason string, args ...interface{}) {
+		violations = append(violations, &Violation{Field: field, Reason: fmt.Sprintf(reason, args...)})
+	}
+
+	if competitor.Name == "" {
+		violate("name", "is required")
+	}
+
+	if len(l.ExpectedNames) > 0 && !contains(l.ExpectedNames, competitor.Name) {
+		violate("name", "is not expected in this shootout")
+	}
+
+	if competitor.Health < l.MinHealth {
+		violate("health", "must be at least %d", l.MinHealth)
+	}
+
+	if l.MaxHealth > 0 && competitor.Health > l.MaxHealth {
+		violate("health", "must be at most %d", l.MaxHealth)
+	}
+
+	if competitor.Damage < 1 {
+		violate("damage", "must be at least 1")
+	}
+
+	if l.MaxDamage > 0 && competitor.Damage > l.MaxDamage {
+		violate("damage", "must be at most %d", l.MaxDamage)
+	}
+
+	if competitor.Accuracy < 0 || competitor.Accuracy > maxAccuracy {
+		violate("accuracy", "must be between 0 and %d", maxAccuracy)
+	}
+
+	if competitor.Armor < 0 {
+		violate("armor", "must not be negative")
+	}
+
+	if competitor.Speed < 0 {
+		violate("speed", "must not be negative")
+	}
+
+	if _, ok := l.Weapons[competitor.Weapon]; competitor.Weapon != "" && !ok {
+		if len(l.Weapons) == 0 {
+			violate("weapon", "is not available in this shootout")
+		} else {
+			violate("weapon", "is unknown")
+		}
+	}
+
+	if len(l.Weapons) > 0 && competitor.Weapon == "" {
+		violate("weapon", "is required")
+	}
+
+	for _, item := range competitor.Items {
+		if _, ok := l.Items[item]; !ok {
+			violate("items", "contain unknown item %q", item)
+		}
+	}
+
+	if cost := Cost(competitor); l.StatBudget > 0 && cost > l.StatBudget {
+		violate("stats", "cost %d points, budget is %d", cost, l.StatBudget)
+	}
+
+	if len(violations) > 0 {
+		return &ValidationError{Violations: violations}
+	}
+
+	return nil
+}
+
+// Cost of competitor's stats in budget points.
+func Cost(competitor *Competitor) int {
+	return competitor.Health*healthCost +
+		competitor.Damage*damageCost +
+		competitor.Accuracy*accuracyCost +
+		competitor.Armor*armorCost +
+		competitor.Speed*speedCost
+}
+
+func contains(values []string, value string) bool {
+	for i := range values {
+		if values[i] == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,108 @@
+package shootout
+
+import (
+	"fmt"
+
+	"github.com/damejeras/shootout/internal/app"
+)
+
+var (
+	ErrReloading = fmt.Errorf("competitor is reloading")
+	ErrOutOfAmmo = fmt.Errorf("competitor is out of ammo")
+)
+
+// armory equips competitors with weapons from the catalog. Without catalog
+// competitors shoot without limits.
+type armory map[string]*app.Weapon
+
+func (a armory) equip(competitor *Competitor) {
+	weapon, ok := a[competitor.Weapon]
+	if !ok {
+		return
+	}
+
+	competitor.Magazine = weapon.Magazine
+	competitor.Ammo = weapon.Ammo
+}
+
+// trigger spends a round from competitor's magazine and starts reloading
+// once the magazine is empty.
+func (a armory) trigger(competitor *Competitor) error {
+	weapon, ok := a[competitor.Weapon]
+	if !ok {
+		return nil
+	}
+
+	if competitor.Reloading > 0 {
+		return ErrReloading
+	}
+
+	if competitor.Magazine == 0 {
+		return ErrOutOfAmmo
+	}
+
+	competitor.Magazine--
+
+	if competitor.Magazine == 0 && competitor.Ammo > 0 {
+		competitor.Reloading = weapon.Reload
+		competitor.reloadStarted = competitor.Reloading > 0
+		if competitor.Reloading == 0 {
+			a.load(competitor, weapon)
+		}
+	}
+
+	return nil
+}
+
+// tick advances reloading by a round. Reloading counts from the round after
+// the magazine was emptied, so reload of N rounds blocks N rounds.
+func (a armory) tick(competitor *Competitor) {
+	weapon, ok := a[competitor.Weapon]
+	if !ok || competitor.Reloading == 0 {
+		return
+	}
+
+	if competitor.reloadStarted {
+		competitor.reloadStarted = false
+		return
+	}
+
+	competitor.Reloading--
+	if competitor.Reloading == 0 {
+		a.load(competitor, weapon)
+	}
+}
+
+func (a armory) load(competitor *Competitor, weapon *app.Weapon) {
+	rounds := weapon.Magazine
+	if rounds > competitor.Ammo {
+		rounds = competitor.Ammo
+	}
+
+	competitor.Magazine += rounds
+	competitor.Ammo -= rounds
+}
+
+// effect of competitor's weapon on the target, if any.
+func (a armory) effect(competitor *Competitor) *app.Effect {
+	weapon, ok := a[competitor.Weapon]
+	if !ok {
+		return nil
+	}
+
+	return weapon.Effect
+}
+
+// damage rolls weapon's damage bonus.
+func (a armory) damage(competitor *Competitor, c *combat) int {
+	weapon, ok := a[competitor.Weapon]
+	if !ok {
+		return 0
+	}
+
+	if weapon.MaxDamage <= weapon.MinDamage {
+		return weapon.MinDamage
+	}
+
+	return weapon.MinDamage + c.rng.Intn(weapon.MaxDamage-weapon.MinDamage+1)
+}
@@ -0,0 +1,78 @@
+package simulation
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/damejeras/shootout/internal/app"
+	"github.com/damejeras/shootout/internal/infrastructure"
+	"github.com/damejeras/shootout/internal/shootout"
+)
+
+var ErrUnexpectedEvent = fmt.Errorf("unexpected event emitted")
+
+// Game is played in-process, without arbiter and shooters talking over
+// redis. Competitors act on the current round, then the round ends.
+type Game struct {
+	state *shootout.State
+	round *shootout.Round
+}
+
+// NewGame registers competitors and starts the game, names of competitors have
+// to be unique.
+func NewGame(cfg *app.ArbiterConfig, competitors []shootout.Competitor) (*Game, error) {
+	game := *cfg
+	game.Competitors = len(competitors)
+	// game starts as soon as everyone registers
+	game.RegistrationTimeout = 0
+
+	g := &Game{state: shootout.NewState(&game)}
+
+	for i := range competitors {
+		if _, err := g.state.Register(&shootout.Registration{Competitor: competitors[i]}); err != nil {
+			return nil, fmt.Errorf("register %s: %w", competitors[i].Name, err)
+		}
+	}
+
+	if err := g.Next(); err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// Round is the current round, as competitors see it.
+func (g *Game) Round() *shootout.Round {
+	return g.round
+}
+
+// Act applies competitor's action to the current round.
+func (g *Game) Act(action *shootout.Action) error {
+	event, err := infrastructure.NewEvent(infrastructure.EventAction, action)
+	if err != nil {
+		return err
+	}
+
+	return g.state.Handle(event)
+}
+
+// Next ends the current round.
+func (g *Game) Next() error {
+	event, err := g.state.Emit()
+	if err != nil {
+		return fmt.Errorf("emit round: %w", err)
+	}
+
+	if event.Type != infrastructure.EventRound {
+		return fmt.Errorf("%w %q", ErrUnexpectedEvent, event.Type)
+	}
+
+	var round shootout.Round
+	if err := json.Unmarshal(event.Data, &round); err != nil {
+		return fmt.Errorf("unmarshal round: %w", err)
+	}
+
+	g.round = &round
+
+	return nil
+}
@@ -0,0 +1,281 @@
+package simulation
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/damejeras/shootout/internal/app"
+	"github.com/damejeras/shootout/internal/shootout"
+	"github.com/damejeras/shootout/internal/strategy"
+)
+
+const (
+	defaultGames      = 1000
+	defaultRoundLimit = 1000
+	// defaultSeed keeps runs repeatable unless seed is given
+	defaultSeed = 1
+)
+
+var (
+	ErrEmptyRoster = fmt.Errorf("roster needs at least two shooters")
+)
+
+// Shooter is a roster entry, it plays by named strategy.
+type Shooter struct {
+	shootout.Competitor
+	Strategy string `json:"strategy,omitempty"`
+}
+
+// Outcome of a single game.
+type Outcome struct {
+	Result *shootout.Result
+	Rounds int
+	// Placements of shooters by ID, shooters eliminated in the same round
+	// share the placement.
+	Placements map[string]float64
+}
+
+// MonteCarlo plays the same roster over and over with different seeds.
+type MonteCarlo struct {
+	game       *app.ArbiterConfig
+	roster     []Shooter
+	strategies map[string]strategy.Strategy
+	games      int
+	workers    int
+	roundLimit int
+	format     string
+}
+
+func NewMonteCarlo(cfg *app.MonteCarloConfig) (*MonteCarlo, error) {
+	m := &MonteCarlo{
+		game:       cfg.Game,
+		strategies: make(map[string]strategy.Strategy),
+		games:      cfg.Games,
+		workers:    cfg.Workers,
+		roundLimit: cfg.RoundLimit,
+		format:     cfg.Format,
+	}
+
+	if err := json.Unmarshal([]byte(cfg.Roster), &m.roster); err != nil {
+		return nil, fmt.Errorf("decode roster: %w", err)
+	}
+
+	if len(m.roster) < 2 {
+		return nil, ErrEmptyRoster
+	}
+
+	for i := range m.roster {
+		// names have to be unique and IDs are referenced by results
+		if m.roster[i].Name == "" {
+			m.roster[i].Name = fmt.Sprintf("shooter_%d", i+1)
+		}
+
+		m.roster[i].ID = m.roster[i].Name
+
+		if m.roster[i].Strategy == "" {
+			m.roster[i].Strategy = strategy.DefaultStrategy
+		}
+
+		play, err := strategy.Lookup(m.roster[i].Strategy)
+		if err != nil {
+			return nil, err
+		}
+
+		m.strategies[m.roster[i].ID] = play
+	}
+
+	if m.format == "" {
+		m.format = FormatTable
+	}
+
+	if _, ok := writers[m.format]; !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownFormat, m.format)
+	}
+
+	if m.games == 0 {
+		m.games = defaultGames
+	}
+
+	if m.workers == 0 {
+		m.workers = runtime.NumCPU()
+	}
+
+	if m.roundLimit == 0 {
+		m.roundLimit = defaultRoundLimit
+	}
+
+	return m, nil
+}
+
+// Run plays all games, game number is added to the seed, so every game is
+// different and every run is the same.
+func (m *MonteCarlo) Run() (*Report, error) {
+	seed := m.game.Seed
+	if seed == 0 {
+		seed = defaultSeed
+	}
+
+	var (
+		outcomes = make([]*Outcome, m.games)
+		jobs     = make(chan int)
+		wg       sync.WaitGroup
+		once     sync.Once
+		failure  error
+	)
+
+	for w := 0; w < m.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range jobs {
+				outcome, err := m.Play(seed + int64(i))
+				if err != nil {
+					once.Do(func() { failure = err })
+					continue
+				}
+
+				outcomes[i] = outcome
+			}
+		}()
+	}
+
+	for i := 0; i < m.games; i++ {
+		jobs <- i
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	if failure != nil {
+		return nil, failure
+	}
+
+	return m.aggregate(outcomes), nil
+}
+
+// Play a single game, shooters act in random order every round.
+func (m *MonteCarlo) Play(seed int64) (*Outcome, error) {
+	cfg := *m.game
+	cfg.Seed = seed
+
+	competitors := make([]shootout.Competitor, len(m.roster))
+	for i := range m.roster {
+		competitors[i] = m.roster[i].Competitor
+	}
+
+	game, err := NewGame(&cfg, competitors)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		rng        = rand.New(rand.NewSource(seed))
+		order      = make([]int, len(m.roster))
+		eliminated = make(map[string]int)
+		rounds     int
+	)
+
+	for i := range order {
+		order[i] = i
+	}
+
+	for game.Round().Result == nil && rounds < m.roundLimit {
+		rounds++
+
+		rng.Shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+
+		round := game.Round()
+		for _, i := range order {
+			shooter := &m.roster[i]
+			if _, ok := round.Competitors[shooter.ID]; !ok {
+				continue
+			}
+
+			action, err := m.strategies[shooter.ID](shooter.ID, shooter.Health, round)
+			if err != nil {
+				continue
+			}
+
+			// rejected action costs shooter the round, as it would in a real game
+			_ = game.Act(action)
+		}
+
+		if err := game.Next(); err != nil {
+			return nil, err
+		}
+
+		for i := range m.roster {
+			id := m.roster[i].ID
+			if _, ok := game.Round().Competitors[id]; !ok && eliminated[id] == 0 {
+				eliminated[id] = rounds
+			}
+		}
+	}
+
+	return &Outcome{
+		Result:     game.Round().Result,
+		Rounds:     rounds,
+		Placements: m.place(game.Round(), eliminated),
+	}, nil
+}
+
+// place ranks survivors by health, winner first, then the eliminated ones
+// from the last to the first.
+func (m *MonteCarlo) place(round *shootout.Round, eliminated map[string]int) map[string]float64 {
+	ids := make([]string, len(m.roster))
+	for i := range m.roster {
+		ids[i] = m.roster[i].ID
+	}
+
+	rank := func(id string) (int, int) {
+		if competitor, ok := round.Competitors[id]; ok {
+			if round.Result != nil && round.Result.Winner == id {
+				return 0, 0
+			}
+
+			return 1, -competitor.Health
+		}
+
+		return 2, -eliminated[id]
+	}
+
+	sort.SliceStable(ids, func(i, j int) bool {
+		gi, ki := rank(ids[i])
+		gj, kj := rank(ids[j])
+		if gi != gj {
+			return gi < gj
+		}
+
+		return ki < kj
+	})
+
+	placements := make(map[string]float64, len(ids))
+	for start := 0; start < len(ids); {
+		end := start + 1
+		for end < len(ids) && sameRank(rank, ids[start], ids[end]) {
+			end++
+		}
+
+		// tied shooters share the mean of placements they occupy
+		placement := float64(start+1+end) / 2
+		for _, id := range ids[start:end] {
+			placements[id] = placement
+		}
+
+		start = end
+	}
+
+	return placements
+}
+
+func sameRank(rank func(string) (int, int), a, b string) bool {
+	ga, ka := rank(a)
+	gb, kb := rank(b)
+
+	return ga == gb && ka == kb
+}
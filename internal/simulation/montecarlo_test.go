@@ -0,0 +1,66 @@
+package simulation
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/damejeras/shootout/internal/app"
+	"github.com/damejeras/shootout/internal/strategy"
+)
+
+func TestMonteCarlo(t *testing.T) {
+	if _, err := NewMonteCarlo(&app.MonteCarloConfig{Roster: `[{"name": "John", "health": 10, "damage": 1}]`, Game: &app.ArbiterConfig{}}); err != ErrEmptyRoster {
+		t.Fatalf("expected err %v, got %v", ErrEmptyRoster, err)
+	}
+
+	monteCarlo, err := NewMonteCarlo(&app.MonteCarloConfig{
+		Roster: `[
+			{"name": "John", "health": 10, "damage": 5},
+			{"name": "Bill", "health": 10, "damage": 1, "strategy": "weakest"},
+			{"name": "Sam", "health": 3, "damage": 1}
+		]`,
+		Games:   10,
+		Workers: 3,
+		Game:    &app.ArbiterConfig{},
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	report, err := monteCarlo.Run()
+	if err != nil {
+		t.Fatalf("unexpected run err: %v", err)
+	}
+
+	// deterministic combat plays the same game every time, John and Sam go
+	// after Bill first as he comes first by ID
+	john, bill, sam := report.Shooters[0], report.Shooters[1], report.Shooters[2]
+	if john.Wins != 10 || john.Placement != 1 || bill.Placement != 3 || sam.Placement != 2 {
+		t.Fatalf("unexpected shooter stats %+v %+v %+v", john, bill, sam)
+	}
+
+	if john.WinRateLow < 0.6 || john.WinRateHigh != 1 || bill.Strategy != "weakest" || sam.Strategy != strategy.DefaultStrategy {
+		t.Fatalf("unexpected shooter stats %+v %+v %+v", john, bill, sam)
+	}
+
+	if report.Length.Min != report.Length.Max || report.Length.Distribution[report.Length.Min] != 10 {
+		t.Fatalf("unexpected game length %+v", report.Length)
+	}
+
+	var output bytes.Buffer
+	if err := monteCarlo.Write(&output, report); err != nil {
+		t.Fatalf("unexpected write err: %v", err)
+	}
+
+	if !strings.Contains(output.String(), "John") {
+		t.Fatalf("report should list shooters, got %q", output.String())
+	}
+
+	if _, err := NewMonteCarlo(&app.MonteCarloConfig{
+		Roster: `[{"name": "John", "health": 10, "damage": 1}, {"name": "Bill", "health": 10, "damage": 1, "strategy": "camper"}]`,
+		Game:   &app.ArbiterConfig{},
+	}); err == nil {
+		t.Fatalf("unknown strategy should be rejected")
+	}
+}
@@ -0,0 +1,228 @@
+package simulation
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+)
+
+const (
+	FormatTable = "table"
+	FormatCSV   = "csv"
+	FormatJSON  = "json"
+	// z-score of 95% confidence
+	confidence = 1.96
+)
+
+var ErrUnknownFormat = fmt.Errorf("unknown report format")
+
+var writers = map[string]func(w io.Writer, report *Report) error{
+	FormatTable: writeTable,
+	FormatCSV:   writeCSV,
+	FormatJSON:  writeJSON,
+}
+
+type Report struct {
+	Games int `json:"games"`
+	// Undecided games hit the round limit.
+	Undecided int `json:"undecided"`
+	// Draws are decided games without a winner.
+	Draws    int            `json:"draws"`
+	Shooters []*ShooterStat `json:"shooters"`
+	Length   *Length        `json:"length"`
+}
+
+type ShooterStat struct {
+	Name     string  `json:"name"`
+	Team     string  `json:"team,omitempty"`
+	Strategy string  `json:"strategy"`
+	Wins     int     `json:"wins"`
+	WinRate  float64 `json:"winRate"`
+	// WinRateLow and WinRateHigh bound 95% confidence interval of win rate.
+	WinRateLow  float64 `json:"winRateLow"`
+	WinRateHigh float64 `json:"winRateHigh"`
+	Placement   float64 `json:"placement"`
+}
+
+// Length of games in rounds, distribution is the number of games by length.
+type Length struct {
+	Mean         float64     `json:"mean"`
+	Min          int         `json:"min"`
+	P25          int         `json:"p25"`
+	Median       int         `json:"median"`
+	P75          int         `json:"p75"`
+	P90          int         `json:"p90"`
+	Max          int         `json:"max"`
+	Distribution map[int]int `json:"distribution"`
+}
+
+// Write report in configured format.
+func (m *MonteCarlo) Write(w io.Writer, report *Report) error {
+	return writers[m.format](w, report)
+}
+
+func (m *MonteCarlo) aggregate(outcomes []*Outcome) *Report {
+	report := &Report{
+		Games:    len(outcomes),
+		Shooters: make([]*ShooterStat, len(m.roster)),
+		Length:   &Length{Distribution: make(map[int]int)},
+	}
+
+	for i := range m.roster {
+		report.Shooters[i] = &ShooterStat{
+			Name:     m.roster[i].Name,
+			Team:     m.roster[i].Team,
+			Strategy: m.roster[i].Strategy,
+		}
+	}
+
+	lengths := make([]int, 0, len(outcomes))
+	for _, outcome := range outcomes {
+		lengths = append(lengths, outcome.Rounds)
+		report.Length.Distribution[outcome.Rounds]++
+
+		switch {
+		case outcome.Result == nil:
+			report.Undecided++
+		case outcome.Result.Winner == "" && outcome.Result.Team == "":
+			report.Draws++
+		}
+
+		for i := range m.roster {
+			stat := report.Shooters[i]
+			stat.Placement += outcome.Placements[m.roster[i].ID]
+
+			if won(outcome, m.roster[i].ID, m.roster[i].Team) {
+				stat.Wins++
+			}
+		}
+	}
+
+	for _, stat := range report.Shooters {
+		stat.Placement /= float64(report.Games)
+		stat.WinRate = float64(stat.Wins) / float64(report.Games)
+		stat.WinRateLow, stat.WinRateHigh = wilson(stat.Wins, report.Games)
+	}
+
+	sort.Ints(lengths)
+
+	sum := 0
+	for _, length := range lengths {
+		sum += length
+	}
+
+	report.Length.Mean = float64(sum) / float64(len(lengths))
+	report.Length.Min = lengths[0]
+	report.Length.P25 = percentile(lengths, 25)
+	report.Length.Median = percentile(lengths, 50)
+	report.Length.P75 = percentile(lengths, 75)
+	report.Length.P90 = percentile(lengths, 90)
+	report.Length.Max = lengths[len(lengths)-1]
+
+	return report
+}
+
+func won(outcome *Outcome, id, team string) bool {
+	if outcome.Result == nil {
+		return false
+	}
+
+	return outcome.Result.Winner == id || (team != "" && outcome.Result.Team == team)
+}
+
+// wilson score interval stays within bounds even for rare wins.
+func wilson(wins, games int) (float64, float64) {
+	n := float64(games)
+	p := float64(wins) / n
+	z2 := confidence * confidence
+
+	center := (p + z2/(2*n)) / (1 + z2/n)
+	margin := confidence * math.Sqrt(p*(1-p)/n+z2/(4*n*n)) / (1 + z2/n)
+
+	return math.Max(0, center-margin), math.Min(1, center+margin)
+}
+
+// percentile of sorted values, nearest rank.
+func percentile(sorted []int, p int) int {
+	rank := int(math.Ceil(float64(p) / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+
+	return sorted[rank-1]
+}
+
+func writeTable(w io.Writer, report *Report) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "%d games, %d draws, %d undecided\n\n", report.Games, report.Draws, report.Undecided)
+	fmt.Fprintln(tw, "SHOOTER\tTEAM\tSTRATEGY\tWINS\tWIN RATE\t95% CI\tAVG PLACEMENT")
+
+	for _, stat := range report.Shooters {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%.1f%%\t%.1f%% - %.1f%%\t%.2f\n",
+			stat.Name, stat.Team, stat.Strategy, stat.Wins,
+			stat.WinRate*100, stat.WinRateLow*100, stat.WinRateHigh*100, stat.Placement)
+	}
+
+	length := report.Length
+	fmt.Fprintln(tw, "\nROUNDS\tMEAN\tMIN\tP25\tMEDIAN\tP75\tP90\tMAX")
+	fmt.Fprintf(tw, "\t%.1f\t%d\t%d\t%d\t%d\t%d\t%d\n",
+		length.Mean, length.Min, length.P25, length.Median, length.P75, length.P90, length.Max)
+
+	return tw.Flush()
+}
+
+// writeCSV writes shooters, then a blank line and distribution of game
+// lengths.
+func writeCSV(w io.Writer, report *Report) error {
+	cw := csv.NewWriter(w)
+
+	records := [][]string{{"shooter", "team", "strategy", "games", "wins", "win_rate", "win_rate_low", "win_rate_high", "placement"}}
+	for _, stat := range report.Shooters {
+		records = append(records, []string{
+			stat.Name,
+			stat.Team,
+			stat.Strategy,
+			strconv.Itoa(report.Games),
+			strconv.Itoa(stat.Wins),
+			strconv.FormatFloat(stat.WinRate, 'f', 4, 64),
+			strconv.FormatFloat(stat.WinRateLow, 'f', 4, 64),
+			strconv.FormatFloat(stat.WinRateHigh, 'f', 4, 64),
+			strconv.FormatFloat(stat.Placement, 'f', 4, 64),
+		})
+	}
+
+	if err := cw.WriteAll(records); err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintln(w); err != nil {
+		return err
+	}
+
+	lengths := make([]int, 0, len(report.Length.Distribution))
+	for length := range report.Length.Distribution {
+		lengths = append(lengths, length)
+	}
+
+	sort.Ints(lengths)
+
+	records = [][]string{{"rounds", "games"}}
+	for _, length := range lengths {
+		records = append(records, []string{strconv.Itoa(length), strconv.Itoa(report.Length.Distribution[length])})
+	}
+
+	return cw.WriteAll(records)
+}
+
+func writeJSON(w io.Writer, report *Report) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(report)
+}
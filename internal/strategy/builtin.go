@@ -0,0 +1,167 @@
+package strategy
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/damejeras/shootout/internal/shootout"
+)
+
+var (
+	ErrNoTarget = fmt.Errorf("no target found")
+)
+
+// Builtin is the strategy shooters play when no bot is given. Health is
+// competitor's starting health, items are saved until it drops to half.
+func Builtin(id string, health int, round *shootout.Round) (*shootout.Action, error) {
+	return play(id, health, round, nearest)
+}
+
+// Spread plays like Builtin, but spreads fire across enemies instead of
+// focusing it on the first one.
+func Spread(id string, health int, round *shootout.Round) (*shootout.Action, error) {
+	return play(id, health, round, next)
+}
+
+// Weakest plays like Builtin, but goes after the enemy with the least health.
+func Weakest(id string, health int, round *shootout.Round) (*shootout.Action, error) {
+	return play(id, health, round, weakest)
+}
+
+func play(id string, health int, round *shootout.Round, pick picker) (*shootout.Action, error) {
+	me, ok := round.Competitors[id]
+	if !ok {
+		return nil, shootout.ErrEliminated
+	}
+
+	if len(me.Items) > 0 && me.Health <= health/2 {
+		return &shootout.Action{Kind: shootout.ActionUse, From: id, Item: me.Items[0]}, nil
+	}
+
+	// nothing to shoot with this round, better hide
+	if me.Weapon != "" && (me.Reloading > 0 || me.Magazine == 0) {
+		return &shootout.Action{Kind: shootout.ActionCover, From: id}, nil
+	}
+
+	target := pick(id, me, round)
+	if target == nil {
+		return nil, ErrNoTarget
+	}
+
+	return approach(id, me, target, round), nil
+}
+
+// picker chooses competitor's target.
+type picker func(id string, me *shootout.Competitor, round *shootout.Round) *shootout.Competitor
+
+// nearest picks the nearest enemy in the arena, otherwise the first one by
+// ID, so seeded games play the same every time.
+func nearest(id string, me *shootout.Competitor, round *shootout.Round) *shootout.Competitor {
+	var target *shootout.Competitor
+	for _, competitorID := range sortedIDs(round) {
+		competitor := round.Competitors[competitorID]
+		if competitorID == id || (me.Team != "" && competitor.Team == me.Team) {
+			continue
+		}
+
+		if round.Arena == nil {
+			return competitor
+		}
+
+		if target == nil ||
+			round.Arena.Distance(*me.Position, *competitor.Position) < round.Arena.Distance(*me.Position, *target.Position) {
+			target = competitor
+		}
+	}
+
+	return target
+}
+
+// next picks the nearest enemy in the arena. Without the arena it is the
+// enemy next to competitor by ID, so fire is spread.
+func next(id string, me *shootout.Competitor, round *shootout.Round) *shootout.Competitor {
+	var target *shootout.Competitor
+	for _, competitorID := range sortedIDs(round) {
+		competitor := round.Competitors[competitorID]
+		if competitorID == id || (me.Team != "" && competitor.Team == me.Team) {
+			continue
+		}
+
+		if round.Arena == nil {
+			if target == nil || (target.ID < id && competitorID > id) {
+				target = competitor
+			}
+
+			continue
+		}
+
+		if target == nil ||
+			round.Arena.Distance(*me.Position, *competitor.Position) < round.Arena.Distance(*me.Position, *target.Position) {
+			target = competitor
+		}
+	}
+
+	return target
+}
+
+func weakest(id string, me *shootout.Competitor, round *shootout.Round) *shootout.Competitor {
+	var target *shootout.Competitor
+	for _, competitorID := range sortedIDs(round) {
+		competitor := round.Competitors[competitorID]
+		if competitorID == id || (me.Team != "" && competitor.Team == me.Team) {
+			continue
+		}
+
+		if target == nil || frailer(competitor, target) {
+			target = competitor
+		}
+	}
+
+	return target
+}
+
+// frailer compares health, or condition when health is concealed by fog of war.
+func frailer(a, b *shootout.Competitor) bool {
+	if a.Condition != "" && b.Condition != "" {
+		return severity[a.Condition] > severity[b.Condition]
+	}
+
+	return a.Health < b.Health
+}
+
+var severity = map[shootout.Condition]int{
+	shootout.ConditionHealthy:  0,
+	shootout.ConditionWounded:  1,
+	shootout.ConditionCritical: 2,
+}
+
+func sortedIDs(round *shootout.Round) []string {
+	ids := make([]string, 0, len(round.Competitors))
+	for id := range round.Competitors {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+
+	return ids
+}
+
+// approach shoots target if it can be hit, otherwise moves towards it.
+func approach(id string, me, target *shootout.Competitor, round *shootout.Round) *shootout.Action {
+	arena := round.Arena
+	if arena == nil || (arena.InRange(*me.Position, *target.Position) && arena.Visible(*me.Position, *target.Position)) {
+		return &shootout.Action{Kind: shootout.ActionShoot, From: id, To: target.ID}
+	}
+
+	var taken []shootout.Position
+	for _, competitor := range round.Competitors {
+		taken = append(taken, *competitor.Position)
+	}
+
+	step, ok := arena.Step(*me.Position, *target.Position, taken)
+	if !ok {
+		return &shootout.Action{Kind: shootout.ActionCover, From: id}
+	}
+
+	return &shootout.Action{Kind: shootout.ActionMove, From: id, Position: &step}
+}
@@ -0,0 +1,43 @@
+package strategy
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/damejeras/shootout/internal/shootout"
+)
+
+const DefaultStrategy = "builtin"
+
+var ErrUnknownStrategy = fmt.Errorf("unknown strategy")
+
+// Strategy decides competitor's action in the round, health is competitor's
+// starting health.
+type Strategy func(id string, health int, round *shootout.Round) (*shootout.Action, error)
+
+var strategies = map[string]Strategy{
+	DefaultStrategy: Builtin,
+	"spread":        Spread,
+	"weakest":       Weakest,
+}
+
+// Lookup finds strategy by name, empty name stands for default strategy.
+func Lookup(name string) (Strategy, error) {
+	if name == "" {
+		name = DefaultStrategy
+	}
+
+	strategy, ok := strategies[name]
+	if !ok {
+		names := make([]string, 0, len(strategies))
+		for name := range strategies {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		return nil, fmt.Errorf("%w %q, available strategies: %v", ErrUnknownStrategy, name, names)
+	}
+
+	return strategy, nil
+}
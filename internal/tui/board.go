@@ -0,0 +1,347 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/damejeras/shootout/internal/infrastructure"
+	"github.com/damejeras/shootout/internal/shootout"
+)
+
+const (
+	clearScreen = "\033[H\033[2J"
+	recentShots = 8
+	barWidth    = 20
+)
+
+type entry struct {
+	shootout.Competitor
+	maxHealth int
+	dealt     int
+	kills     int
+	alive     bool
+}
+
+// Board accumulates game events into a printable scoreboard.
+type Board struct {
+	round       int
+	started     bool
+	finished    bool
+	result      *shootout.Result
+	arena       *shootout.Arena
+	competitors map[string]*entry
+	eliminated  []string
+	shots       []*shootout.Hit
+}
+
+func NewBoard() *Board {
+	return &Board{
+		competitors: make(map[string]*entry),
+	}
+}
+
+func (b *Board) Finished() bool {
+	return b.finished
+}
+
+// Apply updates board with event. Only rounds change the board, shot
+// notifications are taken from round feed.
+func (b *Board) Apply(event *infrastructure.Event) error {
+	if event.Type != infrastructure.EventRound {
+		return nil
+	}
+
+	var round shootout.Round
+	if err := json.Unmarshal(event.Data, &round); err != nil {
+		return fmt.Errorf("unmarshal round: %w", err)
+	}
+
+	b.round++
+	b.started = true
+	b.arena = round.Arena
+
+	for id, competitor := range round.Competitors {
+		known, ok := b.competitors[id]
+		if !ok {
+			known = &entry{maxHealth: competitor.Health}
+			b.competitors[id] = known
+		}
+
+		known.Competitor = *competitor
+		known.alive = true
+	}
+
+	for _, hit := range round.Feed {
+		if shooter, ok := b.competitors[hit.From]; ok && hit.From != hit.To {
+			shooter.dealt += hit.Damage
+			if hit.Killed {
+				shooter.kills++
+			}
+		}
+	}
+
+	for id, known := range b.competitors {
+		if _, ok := round.Competitors[id]; !ok && known.alive {
+			known.alive = false
+			known.Health = 0
+			b.eliminated = append(b.eliminated, id)
+		}
+	}
+
+	b.shots = append(b.shots, round.Feed...)
+	if len(b.shots) > recentShots {
+		b.shots = b.shots[len(b.shots)-recentShots:]
+	}
+
+	if round.Result != nil || len(round.Competitors) <= 1 {
+		b.finished = true
+		b.result = round.Result
+	}
+
+	return nil
+}
+
+func (b *Board) Render(w io.Writer) error {
+	if _, err := fmt.Fprint(w, clearScreen); err != nil {
+		return err
+	}
+
+	if !b.started {
+		_, err := fmt.Fprintln(w, "⏳ waiting for competitors")
+		return err
+	}
+
+	if _, err := fmt.Fprintf(w, "🤠 Shootout — round %d\n\n", b.round); err != nil {
+		return err
+	}
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(table, "NAME\tHEALTH\t\tDAMAGE\tDEALT\tKILLS\t"); err != nil {
+		return err
+	}
+
+	for _, known := range b.standings() {
+		name := known.Name
+		if known.Team != "" {
+			name += " [" + known.Team + "]"
+		}
+
+		if !known.alive {
+			name = "💀 " + name
+		}
+
+		if _, err := fmt.Fprintf(
+			table,
+			"%s\t%s\t%d/%d\t%d\t%d\t%d\t\n",
+			name,
+			bar(known.Health, known.maxHealth),
+			known.Health,
+			known.maxHealth,
+			known.Damage,
+			known.dealt,
+			known.kills,
+		); err != nil {
+			return err
+		}
+	}
+
+	if err := table.Flush(); err != nil {
+		return err
+	}
+
+	if err := b.renderArena(w); err != nil {
+		return err
+	}
+
+	if len(b.shots) > 0 {
+		if _, err := fmt.Fprintln(w, "\nRecent shots:"); err != nil {
+			return err
+		}
+	}
+
+	for _, hit := range b.shots {
+		if _, err := fmt.Fprintf(w, "  %s (-%d)\n", b.describe(hit), hit.Damage); err != nil {
+			return err
+		}
+	}
+
+	if b.finished {
+		return b.renderResult(w)
+	}
+
+	return nil
+}
+
+// renderArena draws the arena with obstacles as # and competitors as the first
+// letter of their name.
+func (b *Board) renderArena(w io.Writer) error {
+	if b.arena == nil {
+		return nil
+	}
+
+	grid := make([][]rune, b.arena.Height)
+	for y := range grid {
+		grid[y] = []rune(strings.Repeat(".", b.arena.Width))
+	}
+
+	for _, obstacle := range b.arena.Obstacles {
+		grid[obstacle.Y][obstacle.X] = '#'
+	}
+
+	for _, known := range b.competitors {
+		if known.alive && known.Position != nil && known.Name != "" {
+			grid[known.Position.Y][known.Position.X] = []rune(known.Name)[0]
+		}
+	}
+
+	if _, err := fmt.Fprintln(w); err != nil {
+		return err
+	}
+
+	for _, row := range grid {
+		if _, err := fmt.Fprintf(w, "  %s\n", string(row)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (b *Board) renderResult(w io.Writer) error {
+	if b.result != nil {
+		switch b.result.Decision {
+		case shootout.DecisionAborted:
+			_, err := fmt.Fprintf(w, "\n🛑 Shootout aborted after %d rounds\n", b.round)
+			return err
+		case shootout.DecisionInsufficientPlayers:
+			_, err := fmt.Fprintln(w, "\n🚫 Not enough players showed up")
+			return err
+		case shootout.DecisionSuddenDeath:
+			if _, err := fmt.Fprintf(w, "\n☠️  Sudden death: %s\n", b.result.SuddenDeath); err != nil {
+				return err
+			}
+
+			if b.result.Winner == "" && b.result.Team == "" {
+				_, err := fmt.Fprintf(w, "🤝 Draw after %d rounds\n", b.round)
+				return err
+			}
+
+			if b.result.Winner != "" {
+				_, err := fmt.Fprintf(w, "🏆 %s wins after %d rounds 🏆\n", b.name(b.result.Winner), b.round)
+				return err
+			}
+		case shootout.DecisionScripted:
+			if b.result.Winner != "" {
+				_, err := fmt.Fprintf(w, "\n📜 %s wins by the rules after %d rounds 📜\n", b.name(b.result.Winner), b.round)
+				return err
+			}
+		}
+
+		if b.result.Team != "" {
+			_, err := fmt.Fprintf(w, "\n🏆 Team %s wins after %d rounds 🏆\n", b.result.Team, b.round)
+			return err
+		}
+	}
+
+	standings := b.standings()
+	if len(standings) == 0 || !standings[0].alive {
+		_, err := fmt.Fprintf(w, "\n☠️  Nobody survived after %d rounds\n", b.round)
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, "\n🏆 %s wins after %d rounds 🏆\n", standings[0].Name, b.round)
+
+	return err
+}
+
+// standings orders living competitors by health, followed by the dead ones
+// starting from the last eliminated.
+func (b *Board) standings() []*entry {
+	var alive []*entry
+	for _, known := range b.competitors {
+		if known.alive {
+			alive = append(alive, known)
+		}
+	}
+
+	sort.Slice(alive, func(i, j int) bool {
+		if alive[i].Health == alive[j].Health {
+			return alive[i].Name < alive[j].Name
+		}
+
+		return alive[i].Health > alive[j].Health
+	})
+
+	for i := len(b.eliminated) - 1; i >= 0; i-- {
+		alive = append(alive, b.competitors[b.eliminated[i]])
+	}
+
+	return alive
+}
+
+func (b *Board) describe(hit *shootout.Hit) string {
+	switch {
+	case hit.Outcome == shootout.OutcomeBleeding && hit.Killed:
+		return fmt.Sprintf("🩸 %s bled out from %s's wound", b.name(hit.To), b.name(hit.From))
+	case hit.Outcome == shootout.OutcomeBleeding:
+		return fmt.Sprintf("🩸 %s bleeds", b.name(hit.To))
+	case hit.Outcome == shootout.OutcomePenalty && hit.Killed:
+		return fmt.Sprintf("⚖️ %s paid for friendly fire with life", b.name(hit.To))
+	case hit.Outcome == shootout.OutcomePenalty:
+		return fmt.Sprintf("⚖️ %s pays for friendly fire", b.name(hit.To))
+	case hit.Outcome == shootout.OutcomeSuddenDeath && hit.Killed:
+		return fmt.Sprintf("☠️ %s did not survive sudden death", b.name(hit.To))
+	case hit.Outcome == shootout.OutcomeSuddenDeath:
+		return fmt.Sprintf("☠️ %s withers in sudden death", b.name(hit.To))
+	case hit.Outcome == shootout.OutcomeScripted && hit.Killed:
+		return fmt.Sprintf("📜 %s was eliminated by the rules", b.name(hit.To))
+	case hit.Outcome == shootout.OutcomeScripted:
+		return fmt.Sprintf("📜 %s loses health by the rules", b.name(hit.To))
+	}
+
+	icon, verb := "🔫", "hit"
+	switch {
+	case hit.Killed:
+		verb = "killed"
+	case hit.Outcome == shootout.OutcomeMiss:
+		icon, verb = "💨", "missed"
+	case hit.Outcome == shootout.OutcomeCritical:
+		icon, verb = "💥", "critically hit"
+	}
+
+	return fmt.Sprintf("%s %s %s %s", icon, b.name(hit.From), verb, b.name(hit.To))
+}
+
+func (b *Board) name(id string) string {
+	if known, ok := b.competitors[id]; ok {
+		return known.Name
+	}
+
+	return id
+}
+
+func bar(health, maxHealth int) string {
+	filled := 0
+	if maxHealth > 0 && health > 0 {
+		filled = health * barWidth / maxHealth
+	}
+
+	if filled > barWidth {
+		filled = barWidth
+	}
+
+	runes := make([]rune, barWidth)
+	for i := range runes {
+		if i < filled {
+			runes[i] = '█'
+		} else {
+			runes[i] = '░'
+		}
+	}
+
+	return string(runes)
+}
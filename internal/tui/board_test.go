@@ -0,0 +1,60 @@
+package tui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/damejeras/shootout/internal/infrastructure"
+	"github.com/damejeras/shootout/internal/shootout"
+)
+
+func TestBoard(t *testing.T) {
+	board := NewBoard()
+
+	rounds := []*shootout.Round{
+		{
+			Competitors: map[string]*shootout.Competitor{
+				"test_1": {ID: "test_1", Name: "Test1", Health: 3, Damage: 1},
+				"test_2": {ID: "test_2", Name: "Test2", Health: 1, Damage: 2},
+			},
+		},
+		{
+			Competitors: map[string]*shootout.Competitor{
+				"test_1": {ID: "test_1", Name: "Test1", Health: 1, Damage: 1},
+			},
+			Feed: []*shootout.Hit{
+				{From: "test_2", To: "test_1", Damage: 2},
+				{From: "test_1", To: "test_2", Damage: 1, Killed: true},
+			},
+		},
+	}
+
+	for i, round := range rounds {
+		event, _ := infrastructure.NewEvent(infrastructure.EventRound, round)
+		if err := board.Apply(event); err != nil {
+			t.Fatalf("unexpected error applying round %d: %v", i, err)
+		}
+	}
+
+	if !board.Finished() {
+		t.Fatalf("board should be finished when one competitor is left")
+	}
+
+	var output bytes.Buffer
+	if err := board.Render(&output); err != nil {
+		t.Fatalf("unexpected render error: %v", err)
+	}
+
+	for _, expected := range []string{
+		"round 2",
+		"💀 Test2",
+		"Test2 hit Test1 (-2)",
+		"Test1 killed Test2 (-1)",
+		"Test1 wins after 2 rounds",
+	} {
+		if !strings.Contains(output.String(), expected) {
+			t.Fatalf("expected board to contain %q, got:\n%s", expected, output.String())
+		}
+	}
+}
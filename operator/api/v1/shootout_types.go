@@ -17,6 +17,7 @@ limitations under the License.
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -24,15 +25,267 @@ type Shooter struct {
 	Name   string `json:"name"`
 	Health int    `json:"health"`
 	Damage int    `json:"damage"`
+	// Accuracy is a hit chance bonus in percent.
+	//+kubebuilder:validation:Minimum=0
+	//+kubebuilder:validation:Maximum=100
+	//+optional
+	Accuracy int `json:"accuracy,omitempty"`
+	// Armor reduces damage of every incoming shot.
+	//+kubebuilder:validation:Minimum=0
+	//+optional
+	Armor int `json:"armor,omitempty"`
+	// Speed makes shooter harder to hit.
+	//+kubebuilder:validation:Minimum=0
+	//+optional
+	Speed int `json:"speed,omitempty"`
+	// Team shooter fights for, shooter without team fights alone.
+	//+optional
+	Team string `json:"team,omitempty"`
+	// Weapon from spec's weapons, required when weapons are defined.
+	//+optional
+	Weapon string `json:"weapon,omitempty"`
+	// Items from spec's items shooter can use once each.
+	//+optional
+	Items []string `json:"items,omitempty"`
+	// Webhook is URL of the bot deciding shooter's actions, it receives every round
+	// and responds with an action. Built-in strategy plays when it is not set.
+	//+optional
+	Webhook string `json:"webhook,omitempty"`
+	// Plugin is WebAssembly strategy deciding shooter's actions, it takes precedence over webhook.
+	//+optional
+	Plugin *Plugin `json:"plugin,omitempty"`
+}
+
+// Plugin is WebAssembly module exporting alloc and choose_target functions, kept in ConfigMap.
+type Plugin struct {
+	ConfigMapKeyRef corev1.ConfigMapKeySelector `json:"configMapKeyRef"`
+	// Timeout limits every call, defaults to 100ms.
+	//+optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// MemoryPages limits plugin's memory in 64KiB pages, defaults to 32.
+	//+kubebuilder:validation:Minimum=1
+	//+kubebuilder:validation:Maximum=65536
+	//+optional
+	MemoryPages int `json:"memoryPages,omitempty"`
+}
+
+// Weapon adds damage rolled between MinDamage and MaxDamage to shooter's damage.
+type Weapon struct {
+	// Magazine is a number of shots before reload.
+	//+kubebuilder:validation:Minimum=1
+	Magazine int `json:"magazine"`
+	// Reload is a number of rounds spent reloading.
+	//+kubebuilder:validation:Minimum=0
+	//+optional
+	Reload int `json:"reload,omitempty"`
+	//+kubebuilder:validation:Minimum=0
+	//+optional
+	MinDamage int `json:"minDamage,omitempty"`
+	//+kubebuilder:validation:Minimum=0
+	//+optional
+	MaxDamage int `json:"maxDamage,omitempty"`
+	// Ammo carried besides loaded magazine.
+	//+kubebuilder:validation:Minimum=0
+	//+optional
+	Ammo int `json:"ammo,omitempty"`
+	// Effect applied to shooters hit with the weapon.
+	//+optional
+	Effect *Effect `json:"effect,omitempty"`
+}
+
+// Effect persists across rounds. Bleeding inflicts amount of damage every round, stunned
+// shooter can not act and shield absorbs amount of damage.
+type Effect struct {
+	//+kubebuilder:validation:Enum=bleeding;stunned;shield
+	Kind string `json:"kind"`
+	//+kubebuilder:validation:Minimum=1
+	Rounds int `json:"rounds"`
+	//+kubebuilder:validation:Minimum=0
+	//+optional
+	Amount int `json:"amount,omitempty"`
+}
+
+// Limits bound shooter stats accepted by arbiter, zero value means stat is not limited.
+type Limits struct {
+	//+optional
+	MinHealth int `json:"minHealth,omitempty"`
+	//+optional
+	MaxHealth int `json:"maxHealth,omitempty"`
+	//+optional
+	MaxDamage int `json:"maxDamage,omitempty"`
+	// StatBudget limits points shooter can spend on stats. Point of health, damage, accuracy,
+	// armor and speed costs 1, 3, 1, 4 and 1 respectively. Stats are free when it is not set,
+	// bounded only by other limits.
+	//+optional
+	StatBudget int `json:"statBudget,omitempty"`
+}
+
+// Combat configures shot resolution. By default every shot hits for shooter's damage.
+type Combat struct {
+	// Probabilistic combat rolls for a hit and for a critical hit on every shot.
+	//+optional
+	Probabilistic bool `json:"probabilistic,omitempty"`
+	// Seed makes probabilistic game reproducible, random seed is used when not set.
+	//+optional
+	Seed int64 `json:"seed,omitempty"`
+	// BaseHitChance in percent, improved by shooter's accuracy and lowered by target's speed. Defaults to 75.
+	//+kubebuilder:validation:Minimum=0
+	//+kubebuilder:validation:Maximum=100
+	//+optional
+	BaseHitChance *int `json:"baseHitChance,omitempty"`
+	// CritChance in percent.
+	//+kubebuilder:validation:Minimum=0
+	//+kubebuilder:validation:Maximum=100
+	//+optional
+	CritChance int `json:"critChance,omitempty"`
+	// CritDamage in percent of shooter's damage. Defaults to 200.
+	//+kubebuilder:validation:Minimum=0
+	//+optional
+	CritDamage int `json:"critDamage,omitempty"`
+}
+
+// Arena is a grid shooters fight in.
+type Arena struct {
+	//+kubebuilder:validation:Minimum=1
+	Width int `json:"width"`
+	//+kubebuilder:validation:Minimum=1
+	Height int `json:"height"`
+	// Range limits shooting distance in cells, shooters can shoot across the whole arena when not set.
+	//+kubebuilder:validation:Minimum=0
+	//+optional
+	Range int `json:"range,omitempty"`
+	// Falloff is a percentage of damage lost for every cell of distance beyond the first.
+	//+kubebuilder:validation:Minimum=0
+	//+kubebuilder:validation:Maximum=100
+	//+optional
+	Falloff int `json:"falloff,omitempty"`
+	// Move is a number of cells shooter can move in a round. Defaults to 1.
+	//+kubebuilder:validation:Minimum=0
+	//+optional
+	Move int `json:"move,omitempty"`
+	// Obstacles block movement and line of sight.
+	//+optional
+	Obstacles []Position `json:"obstacles,omitempty"`
+	// Spawns are taken in order of registration, shooters spawn at random free cells when there are no spawns left.
+	//+optional
+	Spawns []Position `json:"spawns,omitempty"`
+}
+
+type Position struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// Tactics configures actions shooters can take instead of shooting.
+type Tactics struct {
+	// HealAmount is health restored by healing, up to shooter's initial health. Defaults to 2.
+	//+kubebuilder:validation:Minimum=0
+	//+optional
+	HealAmount int `json:"healAmount,omitempty"`
+	// AimBonus is a percentage of damage added to aimed shot. Defaults to 50.
+	//+kubebuilder:validation:Minimum=0
+	//+optional
+	AimBonus int `json:"aimBonus,omitempty"`
+}
+
+// FriendlyFire lets teammates shoot each other.
+type FriendlyFire struct {
+	// Penalty is a percentage of damage inflicted to a teammate which shooter takes as well.
+	//+kubebuilder:validation:Minimum=0
+	//+optional
+	Penalty int `json:"penalty,omitempty"`
+}
+
+// RulesScript is Starlark source of hooks adjusting the ruleset, given inline or by ConfigMap key.
+type RulesScript struct {
+	//+optional
+	Source string `json:"source,omitempty"`
+	//+optional
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+	// MaxSteps limits execution steps of a single hook call, defaults to 100000.
+	//+kubebuilder:validation:Minimum=1
+	//+optional
+	MaxSteps int `json:"maxSteps,omitempty"`
+	// Timeout limits duration of a single hook call, defaults to 100ms.
+	//+optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
 }
 
 // ShootoutSpec defines the desired state of Shootout
 type ShootoutSpec struct {
 	Shooters []Shooter `json:"shooters"`
+	// RegistrationTimeout limits how long arbiter waits for shooters to register.
+	// When it passes game starts with registered shooters, if there are at least MinShooters of them.
+	//+optional
+	RegistrationTimeout *metav1.Duration `json:"registrationTimeout,omitempty"`
+	// TickInterval is the time between rounds, defaults to 1s.
+	//+optional
+	TickInterval *metav1.Duration `json:"tickInterval,omitempty"`
+	// TurnBased game starts the next round as soon as every shooter acted.
+	//+optional
+	TurnBased bool `json:"turnBased,omitempty"`
+	// TurnTimeout limits how long turn-based game waits for shooters to act, defaults to tick interval.
+	//+optional
+	TurnTimeout *metav1.Duration `json:"turnTimeout,omitempty"`
+	// MinShooters required to start the game before all shooters register, defaults to 2.
+	//+kubebuilder:validation:Minimum=1
+	//+optional
+	MinShooters int `json:"minShooters,omitempty"`
+	// FogOfWar sends every shooter its own view of the round, enemies' health is shown
+	// as condition, their damage is hidden until they shoot and their other stats, ammo
+	// and items are hidden.
+	//+optional
+	FogOfWar bool `json:"fogOfWar,omitempty"`
+	// Rules is a name of the ruleset arbiter plays by, defaults to standard.
+	//+optional
+	Rules string `json:"rules,omitempty"`
+	// RulesScript defines on_shot, on_round_end and is_finished hooks, ruleset is played as is without it.
+	//+optional
+	RulesScript *RulesScript `json:"rulesScript,omitempty"`
+	// MaxRounds after which sudden death rule kicks in, game is not limited when not set.
+	//+kubebuilder:validation:Minimum=0
+	//+optional
+	MaxRounds int `json:"maxRounds,omitempty"`
+	// SuddenDeath rule: shrink takes away more health with every round, escalation does the same
+	// and multiplies damage with every round, and health ends the game, shooter with the highest
+	// percentage of health wins.
+	// Defaults to health.
+	//+kubebuilder:validation:Enum=escalation;shrink;health
+	//+optional
+	SuddenDeath string `json:"suddenDeath,omitempty"`
+	// Limits are enforced when shooters register. Only shooters listed in spec can register.
+	//+optional
+	Limits *Limits `json:"limits,omitempty"`
+	//+optional
+	Combat *Combat `json:"combat,omitempty"`
+	//+optional
+	Tactics *Tactics `json:"tactics,omitempty"`
+	// FriendlyFire allows shooting teammates, such shots are rejected when not set.
+	//+optional
+	FriendlyFire *FriendlyFire `json:"friendlyFire,omitempty"`
+	// Items by name, using an item applies its effect to the shooter.
+	//+optional
+	Items map[string]Effect `json:"items,omitempty"`
+	// Arena makes shooters fight on a grid, where distance and obstacles matter.
+	//+optional
+	Arena *Arena `json:"arena,omitempty"`
+	// Weapons by name shooters can choose from.
+	//+optional
+	Weapons map[string]Weapon `json:"weapons,omitempty"`
+	// Paused freezes running game until it is set back to false.
+	//+optional
+	Paused bool `json:"paused,omitempty"`
+	// Aborted ends the game, aborted game can not be resumed.
+	//+optional
+	Aborted bool `json:"aborted,omitempty"`
 }
 
 // ShootoutStatus defines the observed state of Shootout
 type ShootoutStatus struct {
+	// Paused is true when arbiter acknowledged pause.
+	Paused bool `json:"paused,omitempty"`
+	// Aborted is true when arbiter acknowledged abort.
+	Aborted bool `json:"aborted,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -22,12 +22,175 @@ limitations under the License.
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Arena) DeepCopyInto(out *Arena) {
+	*out = *in
+	if in.Obstacles != nil {
+		in, out := &in.Obstacles, &out.Obstacles
+		*out = make([]Position, len(*in))
+		copy(*out, *in)
+	}
+	if in.Spawns != nil {
+		in, out := &in.Spawns, &out.Spawns
+		*out = make([]Position, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Arena.
+func (in *Arena) DeepCopy() *Arena {
+	if in == nil {
+		return nil
+	}
+	out := new(Arena)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Combat) DeepCopyInto(out *Combat) {
+	*out = *in
+	if in.BaseHitChance != nil {
+		in, out := &in.BaseHitChance, &out.BaseHitChance
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Combat.
+func (in *Combat) DeepCopy() *Combat {
+	if in == nil {
+		return nil
+	}
+	out := new(Combat)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Effect) DeepCopyInto(out *Effect) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Effect.
+func (in *Effect) DeepCopy() *Effect {
+	if in == nil {
+		return nil
+	}
+	out := new(Effect)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FriendlyFire) DeepCopyInto(out *FriendlyFire) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FriendlyFire.
+func (in *FriendlyFire) DeepCopy() *FriendlyFire {
+	if in == nil {
+		return nil
+	}
+	out := new(FriendlyFire)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Limits) DeepCopyInto(out *Limits) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Limits.
+func (in *Limits) DeepCopy() *Limits {
+	if in == nil {
+		return nil
+	}
+	out := new(Limits)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Plugin) DeepCopyInto(out *Plugin) {
+	*out = *in
+	in.ConfigMapKeyRef.DeepCopyInto(&out.ConfigMapKeyRef)
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Plugin.
+func (in *Plugin) DeepCopy() *Plugin {
+	if in == nil {
+		return nil
+	}
+	out := new(Plugin)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Position) DeepCopyInto(out *Position) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Position.
+func (in *Position) DeepCopy() *Position {
+	if in == nil {
+		return nil
+	}
+	out := new(Position)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RulesScript) DeepCopyInto(out *RulesScript) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(corev1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RulesScript.
+func (in *RulesScript) DeepCopy() *RulesScript {
+	if in == nil {
+		return nil
+	}
+	out := new(RulesScript)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Shooter) DeepCopyInto(out *Shooter) {
 	*out = *in
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Plugin != nil {
+		in, out := &in.Plugin, &out.Plugin
+		*out = new(Plugin)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Shooter.
@@ -105,7 +268,68 @@ func (in *ShootoutSpec) DeepCopyInto(out *ShootoutSpec) {
 	if in.Shooters != nil {
 		in, out := &in.Shooters, &out.Shooters
 		*out = make([]Shooter, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RegistrationTimeout != nil {
+		in, out := &in.RegistrationTimeout, &out.RegistrationTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.TickInterval != nil {
+		in, out := &in.TickInterval, &out.TickInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.TurnTimeout != nil {
+		in, out := &in.TurnTimeout, &out.TurnTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.RulesScript != nil {
+		in, out := &in.RulesScript, &out.RulesScript
+		*out = new(RulesScript)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Limits != nil {
+		in, out := &in.Limits, &out.Limits
+		*out = new(Limits)
+		**out = **in
+	}
+	if in.Combat != nil {
+		in, out := &in.Combat, &out.Combat
+		*out = new(Combat)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tactics != nil {
+		in, out := &in.Tactics, &out.Tactics
+		*out = new(Tactics)
+		**out = **in
+	}
+	if in.FriendlyFire != nil {
+		in, out := &in.FriendlyFire, &out.FriendlyFire
+		*out = new(FriendlyFire)
+		**out = **in
+	}
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make(map[string]Effect, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Arena != nil {
+		in, out := &in.Arena, &out.Arena
+		*out = new(Arena)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Weapons != nil {
+		in, out := &in.Weapons, &out.Weapons
+		*out = make(map[string]Weapon, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
 	}
 }
 
@@ -133,3 +357,38 @@ func (in *ShootoutStatus) DeepCopy() *ShootoutStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Tactics) DeepCopyInto(out *Tactics) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Tactics.
+func (in *Tactics) DeepCopy() *Tactics {
+	if in == nil {
+		return nil
+	}
+	out := new(Tactics)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Weapon) DeepCopyInto(out *Weapon) {
+	*out = *in
+	if in.Effect != nil {
+		in, out := &in.Effect, &out.Effect
+		*out = new(Effect)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Weapon.
+func (in *Weapon) DeepCopy() *Weapon {
+	if in == nil {
+		return nil
+	}
+	out := new(Weapon)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -18,8 +18,14 @@ package controllers
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	cowboysv1 "github.com/damejeras/shootout/operator/api/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -31,9 +37,15 @@ import (
 )
 
 const (
-	indexField = ".metadata.controller"
+	indexField           = ".metadata.controller"
+	arbiterPort          = "8888"
+	controlRetryInterval = 5 * time.Second
+	pluginDir            = "/plugin"
+	pluginFile           = "plugin.wasm"
 )
 
+var arbiterClient = &http.Client{Timeout: 5 * time.Second}
+
 // ShootoutReconciler reconciles a Shootout object
 type ShootoutReconciler struct {
 	client.Client
@@ -117,6 +129,162 @@ func (r *ShootoutReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		// we have to wait for redis to start
 		return r.Update(shootout)
 	} else if arbiterPod == nil {
+		env := []corev1.EnvVar{
+			{
+				Name:  "COMPETITORS",
+				Value: strconv.Itoa(len(shootout.Spec.Shooters)),
+			},
+			{
+				Name:  "REDIS_ADDR",
+				Value: redisPodIP + ":6379",
+			},
+		}
+
+		if shootout.Spec.MinShooters > 0 {
+			env = append(env, corev1.EnvVar{
+				Name:  "MIN_COMPETITORS",
+				Value: strconv.Itoa(shootout.Spec.MinShooters),
+			})
+		}
+
+		env = append(env, corev1.EnvVar{
+			Name:  "EXPECTED_NAMES",
+			Value: strings.Join(shooterNames(shootout), " "),
+		})
+
+		if limits := shootout.Spec.Limits; limits != nil {
+			for _, limit := range []struct {
+				name  string
+				value int
+			}{
+				{"MIN_HEALTH", limits.MinHealth},
+				{"MAX_HEALTH", limits.MaxHealth},
+				{"MAX_DAMAGE", limits.MaxDamage},
+				{"STAT_BUDGET", limits.StatBudget},
+			} {
+				if limit.value > 0 {
+					env = append(env, corev1.EnvVar{Name: limit.name, Value: strconv.Itoa(limit.value)})
+				}
+			}
+		}
+
+		if combat := shootout.Spec.Combat; combat != nil {
+			env = append(env,
+				corev1.EnvVar{Name: "PROBABILISTIC_COMBAT", Value: strconv.FormatBool(combat.Probabilistic)},
+				corev1.EnvVar{Name: "SEED", Value: strconv.FormatInt(combat.Seed, 10)},
+				corev1.EnvVar{Name: "CRIT_CHANCE", Value: strconv.Itoa(combat.CritChance)},
+				corev1.EnvVar{Name: "CRIT_DAMAGE", Value: strconv.Itoa(combat.CritDamage)},
+			)
+
+			// zero chance is a valid setting, unset one defaults in arbiter
+			if combat.BaseHitChance != nil {
+				env = append(env, corev1.EnvVar{Name: "BASE_HIT_CHANCE", Value: strconv.Itoa(*combat.BaseHitChance)})
+			}
+		}
+
+		if tactics := shootout.Spec.Tactics; tactics != nil {
+			env = append(env,
+				corev1.EnvVar{Name: "HEAL_AMOUNT", Value: strconv.Itoa(tactics.HealAmount)},
+				corev1.EnvVar{Name: "AIM_BONUS", Value: strconv.Itoa(tactics.AimBonus)},
+			)
+		}
+
+		if friendlyFire := shootout.Spec.FriendlyFire; friendlyFire != nil {
+			env = append(env,
+				corev1.EnvVar{Name: "FRIENDLY_FIRE", Value: "true"},
+				corev1.EnvVar{Name: "FRIENDLY_FIRE_PENALTY", Value: strconv.Itoa(friendlyFire.Penalty)},
+			)
+		}
+
+		if len(shootout.Spec.Items) > 0 {
+			items, err := json.Marshal(shootout.Spec.Items)
+			if err != nil {
+				logger.Error(err, "error encoding items")
+				return ctrl.Result{}, err
+			}
+
+			env = append(env, corev1.EnvVar{Name: "ITEMS", Value: string(items)})
+		}
+
+		if shootout.Spec.Arena != nil {
+			arena, err := json.Marshal(shootout.Spec.Arena)
+			if err != nil {
+				logger.Error(err, "error encoding arena")
+				return ctrl.Result{}, err
+			}
+
+			env = append(env, corev1.EnvVar{Name: "ARENA", Value: string(arena)})
+		}
+
+		if len(shootout.Spec.Weapons) > 0 {
+			weapons, err := json.Marshal(shootout.Spec.Weapons)
+			if err != nil {
+				logger.Error(err, "error encoding weapons")
+				return ctrl.Result{}, err
+			}
+
+			env = append(env, corev1.EnvVar{Name: "WEAPONS", Value: string(weapons)})
+		}
+
+		if shootout.Spec.FogOfWar {
+			env = append(env, corev1.EnvVar{Name: "FOG_OF_WAR", Value: "true"})
+		}
+
+		if shootout.Spec.Rules != "" {
+			env = append(env, corev1.EnvVar{Name: "RULES", Value: shootout.Spec.Rules})
+		}
+
+		if script := shootout.Spec.RulesScript; script != nil {
+			if script.ConfigMapKeyRef != nil {
+				env = append(env, corev1.EnvVar{
+					Name:      "RULES_SCRIPT",
+					ValueFrom: &corev1.EnvVarSource{ConfigMapKeyRef: script.ConfigMapKeyRef},
+				})
+			} else {
+				env = append(env, corev1.EnvVar{Name: "RULES_SCRIPT", Value: script.Source})
+			}
+
+			if script.MaxSteps > 0 {
+				env = append(env, corev1.EnvVar{Name: "RULES_SCRIPT_STEPS", Value: strconv.Itoa(script.MaxSteps)})
+			}
+
+			if script.Timeout != nil {
+				env = append(env, corev1.EnvVar{Name: "RULES_SCRIPT_TIMEOUT", Value: script.Timeout.Duration.String()})
+			}
+		}
+
+		if shootout.Spec.MaxRounds > 0 {
+			env = append(env,
+				corev1.EnvVar{Name: "MAX_ROUNDS", Value: strconv.Itoa(shootout.Spec.MaxRounds)},
+				corev1.EnvVar{Name: "SUDDEN_DEATH", Value: shootout.Spec.SuddenDeath},
+			)
+		}
+
+		if shootout.Spec.TickInterval != nil {
+			env = append(env, corev1.EnvVar{
+				Name:  "TICK_INTERVAL",
+				Value: shootout.Spec.TickInterval.Duration.String(),
+			})
+		}
+
+		if shootout.Spec.TurnBased {
+			env = append(env, corev1.EnvVar{Name: "TURN_BASED", Value: "true"})
+		}
+
+		if shootout.Spec.TurnTimeout != nil {
+			env = append(env, corev1.EnvVar{
+				Name:  "TURN_TIMEOUT",
+				Value: shootout.Spec.TurnTimeout.Duration.String(),
+			})
+		}
+
+		if shootout.Spec.RegistrationTimeout != nil {
+			env = append(env, corev1.EnvVar{
+				Name:  "REGISTRATION_TIMEOUT",
+				Value: shootout.Spec.RegistrationTimeout.Duration.String(),
+			})
+		}
+
 		// lets start arbiter pod
 		podDef := &corev1.Pod{
 			ObjectMeta: metav1.ObjectMeta{
@@ -129,18 +297,9 @@ func (r *ShootoutReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 			Spec: corev1.PodSpec{
 				Containers: []corev1.Container{
 					{
-						Name:  "arbiter",
-						Image: "shootout-arbiter:latest",
-						Env: []corev1.EnvVar{
-							{
-								Name:  "COMPETITORS",
-								Value: strconv.Itoa(len(shootout.Spec.Shooters)),
-							},
-							{
-								Name:  "REDIS_ADDR",
-								Value: redisPodIP + ":6379",
-							},
-						},
+						Name:            "arbiter",
+						Image:           "shootout-arbiter:latest",
+						Env:             env,
 						ImagePullPolicy: corev1.PullNever,
 					},
 				},
@@ -167,10 +326,19 @@ func (r *ShootoutReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		return r.Update(shootout)
 	}
 
-	var containers []corev1.Container
+	var (
+		containers []corev1.Container
+		volumes    []corev1.Volume
+	)
 	// we have arbiter and redis running, lets start shooter pods
 	for i := range shootout.Spec.Shooters {
-		containers = append(containers, corev1.Container{
+		key, err := shooterKey()
+		if err != nil {
+			logger.Error(err, "error generating shooter key")
+			return ctrl.Result{}, err
+		}
+
+		container := corev1.Container{
 			Name:  "shooter-" + strings.ToLower(shootout.Spec.Shooters[i].Name),
 			Image: "shootout-shooter:latest",
 			Env: []corev1.EnvVar{
@@ -184,7 +352,15 @@ func (r *ShootoutReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 				},
 				{
 					Name:  "ARBITER_ADDR",
-					Value: "http://" + arbiterPodIP + ":8888",
+					Value: "http://" + arbiterPodIP + ":" + arbiterPort,
+				},
+				{
+					Name:  "HEARTBEAT_TIMEOUT",
+					Value: heartbeatTimeout(shootout).String(),
+				},
+				{
+					Name:  "SHOOTER_KEY",
+					Value: key,
 				},
 				{
 					Name:  "SHOOTER_NAME",
@@ -198,9 +374,50 @@ func (r *ShootoutReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 					Name:  "SHOOTER_DAMAGE",
 					Value: strconv.Itoa(shootout.Spec.Shooters[i].Damage),
 				},
+				{
+					Name:  "SHOOTER_ACCURACY",
+					Value: strconv.Itoa(shootout.Spec.Shooters[i].Accuracy),
+				},
+				{
+					Name:  "SHOOTER_ARMOR",
+					Value: strconv.Itoa(shootout.Spec.Shooters[i].Armor),
+				},
+				{
+					Name:  "SHOOTER_SPEED",
+					Value: strconv.Itoa(shootout.Spec.Shooters[i].Speed),
+				},
+				{
+					Name:  "SHOOTER_TEAM",
+					Value: shootout.Spec.Shooters[i].Team,
+				},
+				{
+					Name:  "SHOOTER_WEAPON",
+					Value: shootout.Spec.Shooters[i].Weapon,
+				},
+				{
+					Name:  "SHOOTER_ITEMS",
+					Value: strings.Join(shootout.Spec.Shooters[i].Items, " "),
+				},
+				{
+					Name:  "SHOOTER_WEBHOOK",
+					Value: shootout.Spec.Shooters[i].Webhook,
+				},
 			},
 			ImagePullPolicy: corev1.PullNever,
-		})
+		}
+
+		if plugin := shootout.Spec.Shooters[i].Plugin; plugin != nil {
+			volume := pluginVolume(container.Name, plugin)
+			volumes = append(volumes, volume)
+			container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+				Name:      volume.Name,
+				MountPath: pluginDir,
+				ReadOnly:  true,
+			})
+			container.Env = append(container.Env, pluginEnv(plugin)...)
+		}
+
+		containers = append(containers, container)
 	}
 
 	shooterPod := &corev1.Pod{
@@ -212,8 +429,10 @@ func (r *ShootoutReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 			},
 		},
 		Spec: corev1.PodSpec{
-			Containers:    containers,
-			RestartPolicy: corev1.RestartPolicyNever,
+			Containers: containers,
+			Volumes:    volumes,
+			// restarted shooter rejoins the game and takes control of its competitor
+			RestartPolicy: corev1.RestartPolicyOnFailure,
 		},
 	}
 
@@ -226,9 +445,135 @@ func (r *ShootoutReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 
 	// TODO: collect corpses
 
+	requeue, err := r.syncControl(ctx, shootout, arbiterPodIP)
+	if err != nil {
+		logger.Error(err, "unable to control arbiter")
+		return ctrl.Result{}, err
+	}
+
+	if requeue {
+		if _, err := r.Update(shootout); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{RequeueAfter: controlRetryInterval}, nil
+	}
+
 	return r.Update(shootout)
 }
 
+// syncControl asks arbiter to pause, resume or abort the game when spec
+// differs from what arbiter has acknowledged. Game can not be paused before
+// it starts, so such request is retried later.
+func (r *ShootoutReconciler) syncControl(ctx context.Context, shootout *cowboysv1.Shootout, arbiterPodIP string) (bool, error) {
+	var action string
+	switch {
+	case shootout.Status.Aborted:
+		return false, nil
+	case shootout.Spec.Aborted:
+		action = "abort"
+	case shootout.Spec.Paused && !shootout.Status.Paused:
+		action = "pause"
+	case !shootout.Spec.Paused && shootout.Status.Paused:
+		action = "resume"
+	default:
+		return false, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://"+arbiterPodIP+":"+arbiterPort+"/"+action, nil)
+	if err != nil {
+		return false, fmt.Errorf("create %s request: %w", action, err)
+	}
+
+	resp, err := arbiterClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("send %s request: %w", action, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+	case resp.StatusCode == http.StatusConflict && action == "pause":
+		return true, nil
+	case resp.StatusCode == http.StatusConflict:
+		// game is already finished or not paused anymore
+	default:
+		return false, fmt.Errorf("unexpected %s response code %d", action, resp.StatusCode)
+	}
+
+	switch action {
+	case "abort":
+		shootout.Status.Aborted = true
+	case "pause":
+		shootout.Status.Paused = true
+	case "resume":
+		shootout.Status.Paused = false
+	}
+
+	return false, nil
+}
+
+// heartbeatTimeout gives arbiter two rounds to send an event to shooters.
+func heartbeatTimeout(shootout *cowboysv1.Shootout) time.Duration {
+	interval := time.Second
+	if shootout.Spec.TickInterval != nil {
+		interval = shootout.Spec.TickInterval.Duration
+	}
+
+	if shootout.Spec.TurnBased && shootout.Spec.TurnTimeout != nil {
+		interval = shootout.Spec.TurnTimeout.Duration
+	}
+
+	return 2 * interval
+}
+
+// shooterKey lets restarted shooter rejoin the game, it stays in the pod spec
+// and is not known to anyone else.
+func shooterKey() (string, error) {
+	key := make([]byte, 16)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(key), nil
+}
+
+// pluginVolume exposes plugin's ConfigMap key to shooter container as a file.
+func pluginVolume(container string, plugin *cowboysv1.Plugin) corev1.Volume {
+	return corev1.Volume{
+		Name: "plugin-" + strings.TrimPrefix(container, "shooter-"),
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: plugin.ConfigMapKeyRef.LocalObjectReference,
+				Items:                []corev1.KeyToPath{{Key: plugin.ConfigMapKeyRef.Key, Path: pluginFile}},
+			},
+		},
+	}
+}
+
+func pluginEnv(plugin *cowboysv1.Plugin) []corev1.EnvVar {
+	env := []corev1.EnvVar{{Name: "SHOOTER_PLUGIN", Value: pluginDir + "/" + pluginFile}}
+
+	if plugin.Timeout != nil {
+		env = append(env, corev1.EnvVar{Name: "SHOOTER_PLUGIN_TIMEOUT", Value: plugin.Timeout.Duration.String()})
+	}
+
+	if plugin.MemoryPages > 0 {
+		env = append(env, corev1.EnvVar{Name: "SHOOTER_PLUGIN_MEMORY_PAGES", Value: strconv.Itoa(plugin.MemoryPages)})
+	}
+
+	return env
+}
+
+func shooterNames(shootout *cowboysv1.Shootout) []string {
+	names := make([]string, len(shootout.Spec.Shooters))
+	for i := range shootout.Spec.Shooters {
+		names[i] = shootout.Spec.Shooters[i].Name
+	}
+
+	return names
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *ShootoutReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &corev1.Pod{}, indexField, func(rawObj client.Object) []string {